@@ -8,7 +8,6 @@ import (
 	"github.com/davidmdm/x/xerr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/nestoca/joy/internal"
 	"github.com/nestoca/joy/internal/yml"
 )
 
@@ -26,6 +25,45 @@ type Promotion struct {
 	AllowAutoMerge   bool     `yaml:"allowAutoMerge,omitempty" json:"allowAutoMerge,omitempty"`
 	FromPullRequests bool     `yaml:"fromPullRequests,omitempty" json:"fromPullRequests,omitempty"`
 	FromEnvironments []string `yaml:"fromEnvironments,omitempty" json:"fromEnvironments,omitempty"`
+
+	// Gates are the conditions releases must satisfy to be promoted to this environment.
+	Gates PromotionGates `yaml:"gates,omitempty" json:"gates,omitzero"`
+}
+
+// PromotionGates are declarative conditions that must be satisfied for releases to be promoted to an environment.
+// They can be explicitly overridden at promotion time, in which case the override is recorded in the pull request.
+type PromotionGates struct {
+	// MinHoursInSource is the minimum number of hours a release version must have been in the source environment.
+	MinHoursInSource int `yaml:"minHoursInSource,omitempty" json:"minHoursInSource,omitempty"`
+
+	// NoPrerelease disallows the promotion of prerelease source versions.
+	NoPrerelease bool `yaml:"noPrerelease,omitempty" json:"noPrerelease,omitempty"`
+
+	// TimeWindows are the time windows during which promotions are allowed (any time if empty).
+	TimeWindows []PromotionTimeWindow `yaml:"timeWindows,omitempty" json:"timeWindows,omitempty"`
+
+	// BlockedDays are the days of the week (e.g. "Friday") during which promotions are not allowed.
+	BlockedDays []string `yaml:"blockedDays,omitempty" json:"blockedDays,omitempty"`
+
+	// TimeZone is the IANA time zone in which time windows and blocked days are evaluated (defaults to UTC).
+	TimeZone string `yaml:"timeZone,omitempty" json:"timeZone,omitempty"`
+
+	// RequireOwnerReviewers requires the owners of released projects to be reviewers of promotion pull requests.
+	RequireOwnerReviewers bool `yaml:"requireOwnerReviewers,omitempty" json:"requireOwnerReviewers,omitempty"`
+}
+
+// PromotionTimeWindow is a daily time window, optionally restricted to some days of the week. A window whose end is
+// before its start crosses midnight, such as 22:00 to 02:00, and applies from the start of each of its days until the
+// end on the following day.
+type PromotionTimeWindow struct {
+	// Days are the days of the week (e.g. "Monday") the window applies to (every day if empty).
+	Days []string `yaml:"days,omitempty" json:"days,omitempty"`
+
+	// Start is the inclusive start time of the window, in HH:MM format.
+	Start string `yaml:"start" json:"start"`
+
+	// End is the exclusive end time of the window, in HH:MM format.
+	End string `yaml:"end" json:"end"`
 }
 
 type EnvironmentSpec struct {
@@ -88,17 +126,22 @@ type Environment struct {
 func (e *Environment) GetStatus() *ResourceStatus { return &e.Status }
 
 func (e Environment) Validate(validChartRefs []string) error {
+	return xerr.MultiErrOrderedFrom(
+		"",
+		validateAgainstSchema(EnvironmentKind, e.File.Tree),
+		e.ValidateChartRefs(validChartRefs),
+	)
+}
+
+// ValidateChartRefs validates that the chart references of the environment are known, independently of its schema.
+func (e Environment) ValidateChartRefs(validChartRefs []string) error {
 	var errs []error
 	for ref := range e.Spec.ChartVersions {
 		if !slices.Contains(validChartRefs, ref) {
 			errs = append(errs, fmt.Errorf("unknown ref: %s", ref))
 		}
 	}
-	return xerr.MultiErrOrderedFrom(
-		"",
-		internal.ValidateAgainstSchema(schemas.Environment, e.File.Tree),
-		xerr.MultiErrOrderedFrom("validating chart references", errs...),
-	)
+	return xerr.MultiErrOrderedFrom("validating chart references", errs...)
 }
 
 func IsValidEnvironment(apiVersion, kind string) bool {
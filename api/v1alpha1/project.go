@@ -5,7 +5,6 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/nestoca/joy/internal"
 	"github.com/nestoca/joy/internal/yml"
 )
 
@@ -65,7 +64,7 @@ type Project struct {
 }
 
 func (project *Project) Validate() error {
-	return internal.ValidateAgainstSchema(schemas.Project, project.File.Tree)
+	return validateAgainstSchema(ProjectKind, project.File.Tree)
 }
 
 func IsValidProject(apiVersion, kind string) bool {
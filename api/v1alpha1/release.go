@@ -9,7 +9,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	sigsyaml "sigs.k8s.io/yaml"
 
-	"github.com/nestoca/joy/internal"
 	"github.com/nestoca/joy/internal/yml"
 )
 
@@ -65,6 +64,10 @@ type ReleaseSpec struct {
 
 	// Links is the map of release-level overrides and additions for release links defined in project and/or catalog configuration.
 	Links map[string]string `yaml:"links,omitempty" json:"links,omitempty"`
+
+	// DependsOn is the list of names of releases of the same environment that must be promoted together with or
+	// before this release, such as a database migration job required by an API.
+	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
 }
 
 type Release struct {
@@ -91,10 +94,14 @@ type Release struct {
 
 	// Environment is the environment that the release is deployed to.
 	Environment *Environment `yaml:"-" json:"-"`
+
+	// Dependencies are the releases of the same environment that this release depends on, as resolved from
+	// Spec.DependsOn. Names that cannot be resolved are left out and reported by validation.
+	Dependencies []*Release `yaml:"-" json:"-"`
 }
 
 func (release Release) Validate() error {
-	return internal.ValidateAgainstSchema(schemas.Release, release.File.Tree)
+	return validateAgainstSchema(ReleaseKind, release.File.Tree)
 }
 
 func (release *Release) UnmarshalYAML(node *yaml.Node) error {
@@ -110,6 +117,7 @@ func (release *Release) FromTree() (*Release, error) {
 	}
 	result.Environment = release.Environment
 	result.Project = release.Project
+	result.Dependencies = release.Dependencies
 	return &result, nil
 }
 
@@ -0,0 +1,84 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/nestoca/joy/internal/yml"
+)
+
+type ReleaseGroupMetadata struct {
+	metav1.ObjectMeta `yaml:",inline"`
+
+	// RelativePath is the catalog-relative path to this resource's yaml file (set only for list JSON/YAML output).
+	RelativePath string `yaml:"relativePath,omitempty" json:"relativePath,omitempty"`
+
+	// AbsolutePath is the absolute path to this resource's yaml file (set only for list JSON/YAML output).
+	AbsolutePath string `yaml:"absolutePath,omitempty" json:"absolutePath,omitempty"`
+}
+
+type ReleaseGroupSpec struct {
+	// Description is a human-readable description of the group, such as the product its releases make up.
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+
+	// Releases is the list of names of releases that are members of the group.
+	Releases []string `yaml:"releases,omitempty" json:"releases,omitempty"`
+
+	// Selectors are `key` or `key=value` label selectors. Releases carrying a matching label are members of the group,
+	// in addition to the releases listed explicitly.
+	Selectors []string `yaml:"selectors,omitempty" json:"selectors,omitempty"`
+}
+
+type ReleaseGroup struct {
+	// ApiVersion is the API version of the release group.
+	ApiVersion string `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty"`
+
+	// Kind is the kind of the release group.
+	Kind string `yaml:"kind,omitempty" json:"kind,omitempty"`
+
+	// ReleaseGroupMetadata is the metadata of the release group.
+	ReleaseGroupMetadata `yaml:"metadata,omitempty" json:"metadata,omitzero"`
+
+	// Spec is the spec of the release group.
+	Spec ReleaseGroupSpec `yaml:"spec,omitempty" json:"spec,omitzero"`
+
+	// File represents the in-memory yaml file of the release group.
+	File *yml.File `yaml:"-" json:"-"`
+}
+
+func (group *ReleaseGroup) Validate() error {
+	return validateAgainstSchema(ReleaseGroupKind, group.File.Tree)
+}
+
+func IsValidReleaseGroup(apiVersion, kind string) bool {
+	return apiVersion == "joy.nesto.ca/v1alpha1" && kind == ReleaseGroupKind
+}
+
+// NewReleaseGroup creates a new release group from given yaml file.
+func NewReleaseGroup(file *yml.File) (*ReleaseGroup, error) {
+	data, err := file.Yaml()
+	if err != nil {
+		return nil, err
+	}
+	var group ReleaseGroup
+	if err := yml.UnmarshalStrict(data, &group); err != nil {
+		return nil, fmt.Errorf("unmarshalling release group: %w", err)
+	}
+	group.File = file
+	return &group, nil
+}
+
+func (group *ReleaseGroup) GetName() string {
+	return group.ReleaseGroupMetadata.Name
+}
+
+// GetReleaseGroupByName returns the release group with given name, or an error if it is not found.
+func GetReleaseGroupByName(groups []*ReleaseGroup, name string) (*ReleaseGroup, error) {
+	for _, group := range groups {
+		if group.Name == name {
+			return group, nil
+		}
+	}
+	return nil, fmt.Errorf("release group %q not found", name)
+}
@@ -27,6 +27,9 @@ package v1alpha1
 
 		// links is the map of release-level overrides and additions for release links defined in project and/or catalog configuration.
 		links?: [string]: string
+
+		// dependsOn is the list of names of releases of the same environment that must be promoted together with or before this release.
+		dependsOn?: [...string]
 	}
 }
 
@@ -41,6 +44,18 @@ package v1alpha1
 			allowAutoMerge?:   bool
 			fromPullRequests?: bool
 			fromEnvironments?: [...string]
+			gates?: {
+				minHoursInSource?: int & >=0
+				noPrerelease?:     bool
+				timeWindows?: [...{
+					days?: [...#weekday]
+					start: #timeOfDay
+					end:   #timeOfDay
+				}]
+				blockedDays?: [...#weekday]
+				timeZone?:              string
+				requireOwnerReviewers?: bool
+			}
 		}
 		cluster?:   string
 		namespace?: string
@@ -93,6 +108,23 @@ package v1alpha1
 	}
 }
 
+#releaseGroup: {
+	apiVersion: #apiVersion
+	kind:       "ReleaseGroup"
+	metadata!:  #metadata
+	spec: {
+		// Description is a human-readable description of the group, such as the product its releases make up.
+		description?: string
+
+		// Releases is the list of names of releases that are members of the group.
+		releases?: [...string]
+
+		// Selectors are `key` or `key=value` label selectors. Releases carrying a matching label are members of the group,
+		// in addition to the releases listed explicitly.
+		selectors?: [...string]
+	}
+}
+
 #apiVersion: "joy.nesto.ca/v1alpha1"
 
 #metadata: {
@@ -102,3 +134,8 @@ package v1alpha1
 	relativePath?: string
 	absolutePath?: string
 }
+
+#weekday: "Monday" | "Tuesday" | "Wednesday" | "Thursday" | "Friday" | "Saturday" | "Sunday"
+
+// Time of day in 24-hour HH:MM format.
+#timeOfDay: =~"^([01][0-9]|2[0-3]):[0-5][0-9]$"
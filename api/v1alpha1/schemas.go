@@ -1,37 +1,65 @@
 package v1alpha1
 
 import (
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
 	"fmt"
+	"sync"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
+	"gopkg.in/yaml.v3"
 
 	"github.com/davidmdm/x/xerr"
 
 	"github.com/nestoca/joy/internal"
+	"github.com/nestoca/joy/internal/yml"
 )
 
 //go:embed schemas.cue
 var schemaText string
 
 type Schemas struct {
-	Release     cue.Value
-	Environment cue.Value
-	Project     cue.Value
+	Release      cue.Value
+	Environment  cue.Value
+	Project      cue.Value
+	ReleaseGroup cue.Value
 }
 
 var schemas Schemas
 
+// schemaPool provides schemas compiled in their own CUE context to each concurrent validation, as CUE values are not
+// safe for concurrent use.
+var schemaPool = sync.Pool{
+	New: func() any {
+		schemas, err := compileSchemas()
+		if err != nil {
+			panic(err)
+		}
+		return schemas
+	},
+}
+
 func init() {
+	compiled, err := compileSchemas()
+	if err != nil {
+		panic(err)
+	}
+	schemas = *compiled
+}
+
+func compileSchemas() (*Schemas, error) {
 	runtime := cuecontext.New()
 	schema := runtime.CompileString(schemaText)
 
+	var result Schemas
 	var errs []error
 	for key, ptr := range map[string]*cue.Value{
-		"environment": &schemas.Environment,
-		"project":     &schemas.Project,
-		"release":     &schemas.Release,
+		"environment":  &result.Environment,
+		"project":      &result.Project,
+		"release":      &result.Release,
+		"releaseGroup": &result.ReleaseGroup,
 	} {
 		*ptr = schema.LookupPath(cue.MakePath(cue.Def(key)))
 		if err := ptr.Validate(); err != nil {
@@ -40,10 +68,45 @@ func init() {
 	}
 
 	if err := xerr.MultiErrOrderedFrom("validating v1alpha1 schemas", errs...); err != nil {
-		panic(err)
+		return nil, err
 	}
+	return &result, nil
+}
+
+// ValidateSchema validates given file against the schema of its kind. It is safe for concurrent use.
+func ValidateSchema(file *yml.File) error {
+	return validateAgainstSchema(file.Kind, file.Tree)
+}
+
+func validateAgainstSchema(kind string, node *yaml.Node) error {
+	pooled := schemaPool.Get().(*Schemas)
+	defer schemaPool.Put(pooled)
+
+	var schema cue.Value
+	switch kind {
+	case EnvironmentKind:
+		schema = pooled.Environment
+	case ProjectKind:
+		schema = pooled.Project
+	case ReleaseKind:
+		schema = pooled.Release
+	case ReleaseGroupKind:
+		schema = pooled.ReleaseGroup
+	default:
+		return fmt.Errorf("no schema for kind %q", kind)
+	}
+
+	return internal.ValidateAgainstSchema(schema, node)
+}
+
+// SchemaDigest returns a digest of the schemas, which changes whenever the schemas do, such that validation results
+// can be cached across runs.
+func SchemaDigest() string {
+	sum := sha256.Sum256([]byte(schemaText))
+	return hex.EncodeToString(sum[:])
 }
 
-func ReleaseSpecification() string     { return internal.StringifySchema(schemas.Release) }
-func EnvironmentSpecification() string { return internal.StringifySchema(schemas.Environment) }
-func ProjectSpecification() string     { return internal.StringifySchema(schemas.Project) }
+func ReleaseSpecification() string      { return internal.StringifySchema(schemas.Release) }
+func EnvironmentSpecification() string  { return internal.StringifySchema(schemas.Environment) }
+func ProjectSpecification() string      { return internal.StringifySchema(schemas.Project) }
+func ReleaseGroupSpecification() string { return internal.StringifySchema(schemas.ReleaseGroup) }
@@ -5,12 +5,13 @@ import (
 )
 
 const (
-	Group           = "joy.nesto.ca"
-	Version         = "v1alpha1"
-	ReleaseKind     = "Release"
-	EnvironmentKind = "Environment"
-	ProjectKind     = "Project"
-	CatalogKind     = "Catalog"
+	Group            = "joy.nesto.ca"
+	Version          = "v1alpha1"
+	ReleaseKind      = "Release"
+	EnvironmentKind  = "Environment"
+	ProjectKind      = "Project"
+	ReleaseGroupKind = "ReleaseGroup"
+	CatalogKind      = "Catalog"
 )
 
 // PreviewLabel marks a release as a preview copy created by `joy release preview`.
@@ -23,24 +24,27 @@ const (
 var GroupVersion = schema.GroupVersion{Group: Group, Version: Version}
 
 var (
-	EnvironmentGK = schema.GroupKind{Group: Group, Kind: EnvironmentKind}
-	ProjectGK     = schema.GroupKind{Group: Group, Kind: ProjectKind}
-	ReleaseGK     = schema.GroupKind{Group: Group, Kind: ReleaseKind}
-	CatalogGK     = schema.GroupKind{Group: Group, Kind: CatalogKind}
+	EnvironmentGK  = schema.GroupKind{Group: Group, Kind: EnvironmentKind}
+	ProjectGK      = schema.GroupKind{Group: Group, Kind: ProjectKind}
+	ReleaseGK      = schema.GroupKind{Group: Group, Kind: ReleaseKind}
+	ReleaseGroupGK = schema.GroupKind{Group: Group, Kind: ReleaseGroupKind}
+	CatalogGK      = schema.GroupKind{Group: Group, Kind: CatalogKind}
 )
 
 var (
-	EnvironmentGVK = schema.GroupVersionKind{Group: Group, Version: Version, Kind: EnvironmentKind}
-	ProjectGVK     = schema.GroupVersionKind{Group: Group, Version: Version, Kind: ProjectKind}
-	ReleaseGVK     = schema.GroupVersionKind{Group: Group, Version: Version, Kind: ReleaseKind}
-	CatalogGVK     = schema.GroupVersionKind{Group: Group, Version: Version, Kind: CatalogKind}
+	EnvironmentGVK  = schema.GroupVersionKind{Group: Group, Version: Version, Kind: EnvironmentKind}
+	ProjectGVK      = schema.GroupVersionKind{Group: Group, Version: Version, Kind: ProjectKind}
+	ReleaseGVK      = schema.GroupVersionKind{Group: Group, Version: Version, Kind: ReleaseKind}
+	ReleaseGroupGVK = schema.GroupVersionKind{Group: Group, Version: Version, Kind: ReleaseGroupKind}
+	CatalogGVK      = schema.GroupVersionKind{Group: Group, Version: Version, Kind: CatalogKind}
 )
 
 // GroupVersionResource identifiers, using the plural resource names defined by
 // the generated CRDs (see joy-operator/cmd/crd-gen).
 var (
-	EnvironmentGVR = GroupVersion.WithResource("environments")
-	ProjectGVR     = GroupVersion.WithResource("projects")
-	ReleaseGVR     = GroupVersion.WithResource("releases")
-	CatalogGVR     = GroupVersion.WithResource("catalogs")
+	EnvironmentGVR  = GroupVersion.WithResource("environments")
+	ProjectGVR      = GroupVersion.WithResource("projects")
+	ReleaseGVR      = GroupVersion.WithResource("releases")
+	ReleaseGroupGVR = GroupVersion.WithResource("releasegroups")
+	CatalogGVR      = GroupVersion.WithResource("catalogs")
 )
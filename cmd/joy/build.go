@@ -42,7 +42,7 @@ Usage: joy build promote [--chart-version <chart-version>] [--exclude-label <key
 			cat := catalog.FromContext(cmd.Context())
 			cat.WithEnvironments([]string{env})
 
-			return build.Promote(build.Opts{
+			_, err := build.Promote(build.Opts{
 				Catalog:       cat,
 				Environment:   env,
 				Project:       project,
@@ -50,7 +50,9 @@ Usage: joy build promote [--chart-version <chart-version>] [--exclude-label <key
 				Writer:        yml.DiskWriter,
 				ChartVersion:  chartVersion,
 				ExcludeLabels: excludeLabels,
+				Out:           cmd.OutOrStdout(),
 			})
+			return err
 		},
 	}
 
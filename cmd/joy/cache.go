@@ -0,0 +1,397 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/davidmdm/x/xerr"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal"
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+func NewCacheCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local joy cache",
+	}
+	cmd.AddCommand(newCacheChartsCmd(preRunConfigs))
+	return cmd
+}
+
+func newCacheChartsCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "charts",
+		Aliases: []string{"chart"},
+		Short:   "Manage the helm charts pulled into the cache",
+	}
+	cmd.AddCommand(newCacheChartsListCmd(preRunConfigs))
+	cmd.AddCommand(newCacheChartsPruneCmd(preRunConfigs))
+	cmd.AddCommand(newCacheChartsVerifyCmd(preRunConfigs))
+	cmd.AddCommand(newCacheChartsWarmCmd(preRunConfigs))
+	return cmd
+}
+
+func newCacheChartsListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var format formatting.Format
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the cached chart versions and their sizes",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			charts, err := chartCacheFromContext(cmd).List()
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case formatting.FormatJson:
+				return formatting.RenderJson(cmd.OutOrStdout(), charts)
+			case formatting.FormatYaml:
+				return formatting.RenderYaml(cmd.OutOrStdout(), charts)
+			case formatting.FormatTable:
+				return renderCachedCharts(cmd.OutOrStdout(), charts)
+			default:
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP((*string)(&format), "format", "f", string(formatting.FormatTable), "output format, one of: table, json, yaml")
+
+	preRunConfigs.SkipCatalog(cmd)
+
+	return cmd
+}
+
+func newCacheChartsPruneCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove cached chart versions not referenced by the catalog",
+		Long: `Remove cached chart versions not referenced by the catalog.
+
+A chart version is referenced when any release of any environment uses it, or when an environment pins it
+with chartVersions. Leftovers of interrupted pulls are always removed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache := chartCacheFromContext(cmd)
+
+			referenced, err := catalogChartDirs(cmd, cache)
+			if err != nil {
+				return err
+			}
+
+			charts, err := cache.List()
+			if err != nil {
+				return err
+			}
+
+			var (
+				count int
+				size  int64
+			)
+			for _, chart := range charts {
+				if !chart.Incomplete && slices.Contains(referenced, chart.Dir) {
+					continue
+				}
+				if !dryRun {
+					if err := cache.Remove(chart); err != nil {
+						return fmt.Errorf("removing %s@%s: %w", chart.Ref, chart.Version, err)
+					}
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🗑️  %s %s@%s (%s)\n", pruneVerb(dryRun), style.Resource(chart.Ref), style.Version(chart.Version), formatSize(chart.Size))
+				count++
+				size += chart.Size
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s %d chart version(s), freeing %s\n", pruneVerb(dryRun), count, formatSize(size))
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only print the chart versions that would be removed")
+
+	preRunConfigs.RequireCatalog(cmd, chartsCatalogRequirements())
+
+	return cmd
+}
+
+func newCacheChartsVerifyCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify cached charts against the digests recorded when they were pulled",
+		Long: `Verify cached charts against the digests recorded when they were pulled.
+
+Fails if any chart was modified since it was pulled or is the leftover of an interrupted pull. Charts pulled
+by older versions of joy have no recorded digest and are reported as unverified.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache := chartCacheFromContext(cmd)
+
+			charts, err := cache.List()
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, chart := range charts {
+				status, err := cache.Verify(chart)
+				if err != nil {
+					return fmt.Errorf("verifying %s@%s: %w", chart.Ref, chart.Version, err)
+				}
+
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %s@%s: %s\n", chartStatusIcon(status), style.Resource(chart.Ref), style.Version(chart.Version), status)
+
+				if status != helm.ChartModified && status != helm.ChartIncomplete {
+					continue
+				}
+
+				if remove {
+					if err := cache.Remove(chart); err != nil {
+						return fmt.Errorf("removing %s@%s: %w", chart.Ref, chart.Version, err)
+					}
+					continue
+				}
+
+				errs = append(errs, fmt.Errorf("%s@%s: %s", chart.Ref, chart.Version, status))
+			}
+
+			return xerr.MultiErrFrom("invalid cached charts (use --remove to remove them)", errs...)
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "remove", false, "remove invalid charts, such that they are pulled again when next needed")
+
+	preRunConfigs.SkipCatalog(cmd)
+
+	return cmd
+}
+
+func newCacheChartsWarmCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var bundle string
+
+	cmd := &cobra.Command{
+		Use:   "warm",
+		Short: "Pull every chart referenced by the catalog into the cache, for offline use",
+		Long: `Pull every chart referenced by the catalog into the cache, for offline use.
+
+With --bundle, the charts are also written to a gzipped tarball that can be used as chart mirror where
+charts cannot be pulled, such as on sandboxed CI runners, either with the --chart-mirror flag or the
+chartMirror entry of .joyrc, along with --offline.`,
+		Example: `  # Prepare for working offline
+  joy cache charts warm
+
+  # Bundle the charts of the catalog, then render releases without pulling charts
+  joy cache charts warm --bundle charts.tar.gz
+  joy release render --offline --chart-mirror charts.tar.gz`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			puller, err := helm.NewPullRenderer(config.FromContext(cmd.Context()).HelmEngine, internal.IoFromCommand(cmd), false)
+			if err != nil {
+				return err
+			}
+
+			cache := chartCacheFromContext(cmd)
+			cache.Puller = puller
+
+			charts, err := catalogCharts(cmd, cache)
+			if err != nil {
+				return err
+			}
+
+			for _, chart := range charts {
+				if _, err := cache.GetChartFS(cmd.Context(), chart); err != nil {
+					return fmt.Errorf("warming %s/%s@%s: %w", chart.RepoURL, chart.Name, chart.Version, err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ %s/%s@%s\n", style.Resource(chart.RepoURL), style.Resource(chart.Name), style.Version(chart.Version))
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "%d chart version(s) cached\n", len(charts))
+			if err != nil || bundle == "" {
+				return err
+			}
+
+			if err := writeChartBundle(cache, charts, bundle); err != nil {
+				return fmt.Errorf("writing bundle %s: %w", bundle, err)
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "📦 Bundled %d chart version(s) in %s\n", len(charts), style.Resource(bundle))
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&bundle, "bundle", "", "also write the charts to given gzipped tarball, for use as chart mirror")
+
+	preRunConfigs.RequireCatalog(cmd, chartsCatalogRequirements())
+
+	return cmd
+}
+
+// writeChartBundle writes the bundle of given cached charts to given path, only replacing any existing file once
+// complete.
+func writeChartBundle(cache helm.ChartCache, charts []helm.Chart, path string) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := cache.WriteBundle(file, charts); err != nil {
+		return err
+	}
+	if err := file.Chmod(0o644); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// chartsCatalogRequirements loads all releases, which is all there is to know about the charts of the catalog, without
+// validating them.
+func chartsCatalogRequirements() CatalogRequirements {
+	return CatalogRequirements{
+		Options: func(*cobra.Command, []string) []catalog.LoadOption {
+			return []catalog.LoadOption{catalog.OnlyKinds(v1alpha1.ReleaseKind), catalog.SkipValidation()}
+		},
+	}
+}
+
+func chartCacheFromContext(cmd *cobra.Command) helm.ChartCache {
+	return newChartCache(cmd, config.FromContext(cmd.Context()))
+}
+
+// newChartCache returns the chart cache for given config, which may differ from the one of the context when rendering
+// the catalog at other revisions, honoring the global offline and chart mirror flags.
+func newChartCache(cmd *cobra.Command, cfg *config.Config) helm.ChartCache {
+	cache := helm.ChartCache{
+		Refs:            cfg.Charts,
+		DefaultChartRef: cfg.DefaultChartRef,
+		Root:            cfg.JoyCache,
+		Lock:            cfg.ChartLock,
+		Mirror:          cfg.ChartMirror,
+	}
+	if flags := config.FlagsFromContext(cmd.Context()); flags != nil {
+		cache.Mirror = cmp.Or(flags.ChartMirror, cache.Mirror)
+		cache.Offline = flags.Offline
+	}
+	return cache
+}
+
+// catalogCharts returns the charts referenced by the releases and environments of the catalog.
+func catalogCharts(cmd *cobra.Command, cache helm.ChartCache) ([]helm.Chart, error) {
+	cat := catalog.FromContext(cmd.Context())
+
+	var releases []*v1alpha1.Release
+	for _, item := range cat.Releases.Items {
+		for _, release := range item.Releases {
+			if release != nil {
+				releases = append(releases, release)
+			}
+		}
+	}
+
+	charts, err := cache.CatalogCharts(releases, cat.Environments)
+	if err != nil {
+		return nil, fmt.Errorf("getting charts of catalog: %w", err)
+	}
+	return charts, nil
+}
+
+// catalogChartDirs returns the cache directories of the charts referenced by the catalog.
+func catalogChartDirs(cmd *cobra.Command, cache helm.ChartCache) ([]string, error) {
+	charts, err := catalogCharts(cmd, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0, len(charts))
+	for _, chart := range charts {
+		dir, err := cache.ChartDir(chart)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, filepath.Clean(dir))
+	}
+	return dirs, nil
+}
+
+func renderCachedCharts(writer io.Writer, charts []helm.CachedChart) error {
+	if len(charts) == 0 {
+		_, err := fmt.Fprintln(writer, "No cached charts")
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"CHART", "VERSION", "SIZE"})
+
+	var total int64
+	for _, chart := range charts {
+		version := style.Version(chart.Version)
+		if chart.Incomplete {
+			version += " (incomplete)"
+		}
+		t.AppendRow(table.Row{chart.Ref, version, formatSize(chart.Size)})
+		total += chart.Size
+	}
+
+	if _, err := io.WriteString(writer, t.Render()+"\n"); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(writer, "%d chart version(s), %s\n", len(charts), formatSize(total))
+	return err
+}
+
+func chartStatusIcon(status helm.ChartStatus) string {
+	switch status {
+	case helm.ChartValid:
+		return "✅"
+	case helm.ChartUnverified:
+		return "❔"
+	default:
+		return "❌"
+	}
+}
+
+func pruneVerb(dryRun bool) string {
+	if dryRun {
+		return "Would remove"
+	}
+	return "Removed"
+}
+
+// formatSize formats given number of bytes in human-readable binary units.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -1,31 +1,96 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
 
+	"github.com/nestoca/joy/api/v1alpha1"
 	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/release/diff"
+	"github.com/nestoca/joy/pkg/catalog"
 )
 
-// shouldSkipCatalogLoad reports commands that only need config, not a parsed catalog tree.
-func shouldSkipCatalogLoad(cmd *cobra.Command) bool {
-	return cmd != nil && cmd.Name() == "dir" && cmd.Parent() != nil && cmd.Parent().Name() == "catalog"
+// CatalogRequirements declares what a command needs from the catalog, such that the root command only loads that.
+// The zero value loads and validates the whole catalog.
+type CatalogRequirements struct {
+	// Skip is for commands that only need config, not a parsed catalog tree.
+	Skip bool
+
+	// Options returns the options to load the catalog with, given the parsed flags and args of the command.
+	Options func(cmd *cobra.Command, args []string) []catalog.LoadOption
+
+	// Ref is the git revision to read the catalog from rather than from its working copy, if any.
+	Ref *string
+}
+
+func (requirements CatalogRequirements) loadOptions(cmd *cobra.Command, args []string) []catalog.LoadOption {
+	if requirements.Options == nil {
+		return nil
+	}
+	return requirements.Options(cmd, args)
+}
+
+func (requirements CatalogRequirements) ref() string {
+	if requirements.Ref == nil {
+		return ""
+	}
+	return *requirements.Ref
+}
+
+// addCatalogRefFlag allows reading the catalog of a read-only command from a git revision rather than from the working
+// copy, which is then neither pulled nor required to be clean.
+func addCatalogRefFlag(cmd *cobra.Command, preRunConfigs PreRunConfigs) {
+	var ref string
+	cmd.Flags().StringVar(&ref, "catalog-ref", "", "Git revision (branch, tag or commit) to read the catalog from instead of the working copy")
+
+	cfg := preRunConfigs[cmd]
+	cfg.Catalog.Ref = &ref
+	preRunConfigs[cmd] = cfg
 }
 
-func NewCatalogCmd() *cobra.Command {
+// releaseCatalogRequirements loads only the environment and release that a read-only release command is about, when
+// they are specified, without validating them.
+func releaseCatalogRequirements(env *string) CatalogRequirements {
+	return CatalogRequirements{
+		Options: func(cmd *cobra.Command, args []string) []catalog.LoadOption {
+			opts := []catalog.LoadOption{catalog.OnlyKinds(v1alpha1.ReleaseKind), catalog.SkipValidation()}
+			if *env != "" {
+				opts = append(opts, catalog.OnlyEnvironments(*env))
+			}
+			if len(args) > 0 {
+				opts = append(opts, catalog.OnlyReleases(args[0]))
+			}
+			return opts
+		},
+	}
+}
+
+// kindsCatalogRequirements loads only resources of given kinds.
+func kindsCatalogRequirements(kinds ...string) CatalogRequirements {
+	return CatalogRequirements{
+		Options: func(*cobra.Command, []string) []catalog.LoadOption {
+			return []catalog.LoadOption{catalog.OnlyKinds(kinds...)}
+		},
+	}
+}
+
+func NewCatalogCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "catalog",
 		Aliases: []string{"cat"},
 		Short:   "Catalog path and metadata",
 	}
-	cmd.AddCommand(newCatalogDirCmd())
+	cmd.AddCommand(newCatalogDirCmd(preRunConfigs))
+	cmd.AddCommand(newCatalogDiffCmd(preRunConfigs))
 	return cmd
 }
 
-func newCatalogDirCmd() *cobra.Command {
-	return &cobra.Command{
+func newCatalogDirCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	cmd := &cobra.Command{
 		Use:     "directory",
 		Aliases: []string{"dir"},
 		Short:   "Print the absolute catalog directory path",
@@ -41,4 +106,66 @@ func newCatalogDirCmd() *cobra.Command {
 			return err
 		},
 	}
+	preRunConfigs.SkipCatalog(cmd)
+	return cmd
+}
+
+func newCatalogDiffCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var format formatting.Format
+
+	cmd := &cobra.Command{
+		Use:   "diff <base> [head]",
+		Short: "Show release changes between two git revisions of the catalog",
+		Long: `Show release changes between two git revisions of the catalog.
+
+Loads the catalog at both revisions, directly from git without touching the working copy, and reports for
+each environment which releases were added, removed, or had their version, chart or values changed. Changes
+to values locked with !lock in either revision are annotated with 🔒.
+
+If head is omitted, the working copy of the catalog is compared to base.`,
+		Example: `  # Show release changes of a promotion pull request
+  joy catalog diff origin/master origin/pr-branch
+
+  # Output changes as markdown, for instance to post as a pull request comment
+  joy catalog diff origin/master HEAD -f markdown`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+
+			load := func(ref string) (*catalog.Catalog, error) {
+				// Older revisions may reference charts unknown to the current configuration, and what changed
+				// is worth reporting whether or not it is valid.
+				opts := []catalog.LoadOption{catalog.SkipValidation()}
+				if ref != "" {
+					opts = append(opts, catalog.AtRevision(ref))
+				}
+				cat, err := catalog.Load(cmd.Context(), cfg.CatalogDir, cfg.KnownChartRefs(), opts...)
+				if err != nil {
+					return nil, fmt.Errorf("loading catalog at %s: %w", cmp.Or(ref, "working copy"), err)
+				}
+				return cat, nil
+			}
+
+			params := diff.Params{BaseRef: args[0], HeadRef: "(working copy)"}
+			if len(args) > 1 {
+				params.HeadRef = args[1]
+			}
+
+			var err error
+			if params.Base, err = load(args[0]); err != nil {
+				return err
+			}
+			if params.Head, err = load(cmp.Or(args[1:]...)); err != nil {
+				return err
+			}
+
+			return diff.Render(cmd.OutOrStdout(), diff.Compute(params), format)
+		},
+	}
+
+	cmd.Flags().StringVarP((*string)(&format), "format", "f", string(formatting.FormatTable), "output format, one of: table, markdown, json, yaml")
+
+	preRunConfigs.SkipCatalog(cmd)
+
+	return cmd
 }
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal"
+	"github.com/nestoca/joy/internal/charts/upgrade"
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/github"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+func NewChartsCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "charts",
+		Aliases: []string{"chart"},
+		Short:   "Manage the helm charts referenced by the catalog",
+	}
+	cmd.AddCommand(newChartsLockCmd(preRunConfigs))
+	cmd.AddCommand(newChartsUpgradeCmd(preRunConfigs))
+	return cmd
+}
+
+func newChartsLockCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Record the digests of the chart versions referenced by the catalog in charts.lock",
+		Long: `Record the digests of the chart versions referenced by the catalog in charts.lock.
+
+Every chart version referenced by a release or pinned by an environment is pulled, if not already cached,
+and its digests recorded in the charts.lock file at the root of the catalog: the digest of OCI charts in
+their registry, as reported by helm push, and a content digest of the files of every chart, computed by
+joy. Once committed, rendering a release fails if its chart no longer matches the recorded digests, for
+instance because its version was overwritten in the registry.
+
+Digests of cached charts are trusted as recorded when they were pulled. Run "joy cache charts verify"
+first if in doubt.`,
+		Example: `  # Lock chart versions after changing chart references or versions
+  joy charts lock
+
+  # Fail if charts.lock is not up to date, for instance in CI
+  joy charts lock --check`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+
+			puller, err := helm.NewPullRenderer(cfg.HelmEngine, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}, false)
+			if err != nil {
+				return err
+			}
+
+			// Versions are resolved anew rather than verified against the existing lock
+			cache := chartCacheFromContext(cmd)
+			cache.Lock = nil
+			cache.Puller = puller
+
+			charts, err := catalogCharts(cmd, cache)
+			if err != nil {
+				return err
+			}
+
+			lock := &helm.ChartLock{Charts: []helm.LockedChart{}}
+			for _, chart := range charts {
+				locked, err := cache.LockChart(cmd.Context(), chart)
+				if err != nil {
+					return fmt.Errorf("resolving digests of %s/%s@%s: %w", chart.RepoURL, chart.Name, chart.Version, err)
+				}
+				lock.Charts = append(lock.Charts, locked)
+			}
+
+			if check {
+				if !lock.Equal(cfg.ChartLock) {
+					return fmt.Errorf("%s is not up to date: run joy charts lock", helm.ChartLockFile)
+				}
+				_, err := fmt.Fprintf(cmd.OutOrStdout(), "✅ %s is up to date\n", helm.ChartLockFile)
+				return err
+			}
+
+			path := filepath.Join(cfg.CatalogDir, helm.ChartLockFile)
+			if err := lock.Save(path); err != nil {
+				return fmt.Errorf("saving %s: %w", path, err)
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "🔒 Locked %d chart version(s) in %s\n", len(lock.Charts), style.Resource(path))
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "fail if charts.lock is not up to date instead of writing it")
+
+	preRunConfigs.PullCatalog(cmd)
+	preRunConfigs.RequireCatalog(cmd, chartsCatalogRequirements())
+
+	return cmd
+}
+
+func newChartsUpgradeCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var (
+		toVersion          string
+		environments       []string
+		noRender, createPR bool
+		draft, dryRun      bool
+		reviewers          []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upgrade <ref> --to <version>",
+		Short: "Upgrade a chart reference to a new version across environments",
+		Long: `Upgrade a chart reference to a new version across environments.
+
+Updates the chartVersions entry of selected environments for given chart reference, as well as the chart
+version of releases that pin their own version of it, preserving all other values and their !lock/!local
+tags. Environments whose releases follow the default version of the chart reference get a chartVersions
+entry.
+
+Affected releases are first rendered with both their current and the new chart version, and nothing is
+written if any of them cannot be rendered with the new version, for instance because their values no
+longer match the schema of the chart.`,
+		Example: `  # Upgrade the generic chart in staging, only writing the changes to the working tree
+  joy charts upgrade generic --to 2.0.0 -e staging
+
+  # Upgrade the generic chart everywhere, with one pull request per environment
+  joy charts upgrade generic --to 2.0.0 --pr`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+
+			for _, env := range environments {
+				if _, err := v1alpha1.GetEnvironmentByName(cat.Environments, env); err != nil {
+					return err
+				}
+			}
+
+			puller, err := helm.NewPullRenderer(cfg.HelmEngine, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}, false)
+			if err != nil {
+				return err
+			}
+
+			pullRenderer, err := helm.NewPullRenderer(cfg.HelmEngine, internal.IoFromCommand(cmd), false)
+			if err != nil {
+				return err
+			}
+
+			cache := chartCacheFromContext(cmd)
+			cache.Puller = puller
+
+			upgrader := upgrade.Upgrader{
+				GitProvider:         promote.NewShellGitProvider(cfg.CatalogDir),
+				PullRequestProvider: github.NewPullRequestProvider(cfg.CatalogDir),
+				YamlWriter:          yml.DiskWriter,
+				ChartCache:          cache,
+				Helm:                pullRenderer,
+				Out:                 cmd.OutOrStdout(),
+			}
+
+			_, err = upgrader.Upgrade(cmd.Context(), upgrade.Opts{
+				Catalog:      cat,
+				Ref:          args[0],
+				ToVersion:    toVersion,
+				Environments: environments,
+				NoRender:     noRender,
+				PullRequest:  createPR,
+				Draft:        draft,
+				DryRun:       dryRun,
+				Reviewers:    reviewers,
+			})
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&toVersion, "to", "", "Chart version to upgrade to")
+	cmd.Flags().StringSliceVarP(&environments, "env", "e", nil, "Environments to upgrade (defaults to all environments)")
+	cmd.Flags().BoolVar(&noRender, "no-render", false, "Skip rendering affected releases with the new chart version")
+	cmd.Flags().BoolVar(&createPR, "pr", false, "Create a pull request per environment instead of only writing changes to the working tree")
+	cmd.Flags().BoolVar(&draft, "draft", false, "Create draft PRs")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Dry run (do not write files nor create PRs)")
+	cmd.Flags().StringSliceVar(&reviewers, "reviewers", nil, "Additional reviewers to add to the PRs (can be specified multiple times)")
+	_ = cmd.MarkFlagRequired("to")
+
+	preRunConfigs.PullCatalog(cmd)
+	preRunConfigs.RequireCatalog(cmd, chartsCatalogRequirements())
+
+	return cmd
+}
@@ -47,6 +47,7 @@ func NewEnvironmentListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	}
 	formatting.AddFormatFlag(cmd, &format)
 	preRunConfigs.PullCatalog(cmd)
+	preRunConfigs.RequireCatalog(cmd, kindsCatalogRequirements(v1alpha1.EnvironmentKind))
 	return cmd
 }
 
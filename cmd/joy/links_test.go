@@ -15,7 +15,7 @@ import (
 )
 
 func TestReleaseLinks(t *testing.T) {
-	actual := executeLinksCommand(t, NewReleaseLinksCmd(), "--env", "staging", "my-release")
+	actual := executeLinksCommand(t, NewReleaseLinksCmd(make(PreRunConfigs)), "--env", "staging", "my-release")
 	expected := `╭──────────────────────────────────┬───────────────────────────────────────────────────────────────────╮
 │ NAME                             │ URL                                                               │
 ├──────────────────────────────────┼───────────────────────────────────────────────────────────────────┤
@@ -33,7 +33,7 @@ func TestReleaseLinks(t *testing.T) {
 }
 
 func TestReleaseSpecificLink(t *testing.T) {
-	actual := executeLinksCommand(t, NewReleaseLinksCmd(), "--env", "staging", "my-release", "actions")
+	actual := executeLinksCommand(t, NewReleaseLinksCmd(make(PreRunConfigs)), "--env", "staging", "my-release", "actions")
 	expected := "https://github.com/acme/my-project/actions"
 	require.Equal(t, expected, actual)
 }
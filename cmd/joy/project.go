@@ -46,6 +46,7 @@ func NewProjectListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	}
 	formatting.AddFormatFlag(cmd, &format)
 	preRunConfigs.PullCatalog(cmd)
+	preRunConfigs.RequireCatalog(cmd, kindsCatalogRequirements(v1alpha1.ProjectKind))
 	return cmd
 }
 
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"runtime"
 	"slices"
 	"strings"
 
@@ -53,26 +54,43 @@ func NewReleaseCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	cmd.AddCommand(NewReleasePromoteCmd(PromoteParams{PreRunConfigs: preRunConfigs}))
 	cmd.AddCommand(NewReleaseSelectCmd(preRunConfigs))
 	cmd.AddCommand(NewReleaseRenderCmd())
-	cmd.AddCommand(NewReleaseOpenCmd())
-	cmd.AddCommand(NewReleaseLinksCmd())
+	cmd.AddCommand(NewReleaseOpenCmd(preRunConfigs))
+	cmd.AddCommand(NewReleaseLinksCmd(preRunConfigs))
 	cmd.AddCommand(NewReleaseSchemaCmd())
 	cmd.AddCommand(NewReleasePreviewCmd())
+	cmd.AddCommand(NewReleaseRollbackCmd(preRunConfigs))
+	cmd.AddCommand(NewReleaseHistoryCmd(preRunConfigs))
+	cmd.AddCommand(NewReleaseDriftCmd(preRunConfigs))
 	cmd.AddCommand(NewGitCommands())
-	cmd.AddCommand(NewValidateCommand())
+	cmd.AddCommand(NewValidateCommand(preRunConfigs))
 
 	return cmd
 }
 
 func NewReleaseListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
-	var commaSeparatedEnvs, owners string
+	var commaSeparatedEnvs, owners, group, selector, where string
 	var narrow, wide bool
 	var format formatting.Format
 	var onlySelection, ignoreSelection bool
 	cmd := &cobra.Command{
-		Use:     "list",
+		Use:     "list [pattern1,pattern2...]",
 		Aliases: []string{"ls", "l"},
 		Args:    cobra.RangeArgs(0, 1),
 		Short:   "List releases across environments",
+		Example: `  # Releases matching exact names, globs or /regular expressions/
+  joy release list 'api,payments-*,/^worker-(eu|ca)$/'
+
+  # Releases of a release group
+  joy release list --group checkout
+
+  # Releases matching a label selector
+  joy release list --selector 'tier in (api,worker),!preview'
+
+  # Releases matching a CUE expression over their release, env and project, with spec fields such as values
+  # available directly on each of them (list, strings, strconv and regexp packages can be used without import).
+  # Versions are strings, so match them with regular expressions, as ordering operators are rejected on them.
+  joy release list --where 'env.name == "prod" && release.chart.version =~ "^1\\." && list.Contains(project.owners, "team-a")'
+  joy release list --where 'release.values.replicas > 2'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
@@ -98,8 +116,9 @@ func NewReleaseListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 
 			// Filter releases
 			if len(args) > 0 {
-				releasePattern := args[0]
-				cat.WithReleaseFilter(filtering.NewNamePatternFilter(releasePattern))
+				if err := cat.WithReleasePatterns(filtering.SplitNamePatterns(args[0])); err != nil {
+					return err
+				}
 			} else if len(cfg.Releases.Selected) > 0 && shouldFilterBySelection {
 				cat.WithReleaseFilter(filtering.NewSpecificReleasesFilter(cfg.Releases.Selected))
 			}
@@ -109,6 +128,34 @@ func NewReleaseListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 				cat.WithReleaseFilter(filtering.NewOwnerFilter(owners))
 			}
 
+			// Filter releases by group
+			if group != "" {
+				if err := cat.WithReleaseGroup(group); err != nil {
+					return err
+				}
+			}
+
+			// Filter releases by label selector
+			if selector != "" {
+				filter, err := filtering.NewLabelSelectorFilter(selector)
+				if err != nil {
+					return err
+				}
+				cat.WithReleaseFilter(filter)
+			}
+
+			// Filter releases by expression
+			if where != "" {
+				filter, err := filtering.NewExpressionFilter(where)
+				if err != nil {
+					return err
+				}
+				cat.WithReleaseFilter(filter)
+				if err := filter.Err(); err != nil {
+					return err
+				}
+			}
+
 			releaseList, err := list.GetReleaseList(cat, list.Params{
 				Environments:         environments,
 				ReferenceEnvironment: cfg.ReferenceEnvironment,
@@ -122,12 +169,16 @@ func NewReleaseListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&commaSeparatedEnvs, "env", "e", "", "environments to list (comma-separated, defaults to configured selection or all)")
 	cmd.Flags().StringVarP(&owners, "owners", "o", "", "List releases by owners (comma-separated, defaults to all)")
+	cmd.Flags().StringVarP(&group, "group", "g", "", "List releases of given release group")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "List releases matching label selector (e.g. team=fe,tier in (api,worker),!preview)")
+	cmd.Flags().StringVar(&where, "where", "", `List releases matching CUE expression over release, env and project (e.g. 'env.name == "prod" && list.Contains(project.owners, "team-a")')`)
 	cmd.Flags().BoolVarP(&narrow, "narrow", "n", false, "Use narrow columns mode")
 	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Use wide columns mode")
 	cmd.Flags().BoolVar(&onlySelection, "only-selection", false, "only render selected items (default for table output)")
 	cmd.Flags().BoolVar(&ignoreSelection, "ignore-selection", false, "ignore selection and render all items (default for non-table output)")
 	cmd.MarkFlagsMutuallyExclusive("narrow", "wide")
 	formatting.AddFormatFlag(cmd, &format)
+	addCatalogRefFlag(cmd, preRunConfigs)
 
 	preRunConfigs.PullCatalog(cmd)
 
@@ -145,15 +196,16 @@ type PromoteParams struct {
 }
 
 func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
-	var sourceEnv, targetEnv string
+	var sourceEnv, targetEnv, group, selector string
 	var autoMerge, draft, dryRun, localOnly, noPrompt, narrow, wide bool
-	var all, keepPrerelease bool
-	var omit []string
+	var all, keepPrerelease, stacked, withDependencies bool
+	var omit, path, overrideGates []string
 	var templateVars []string
 	var reviewers []string
+	var output string
 
 	cmd := &cobra.Command{
-		Use:     "promote [flags] [release1,release2...]",
+		Use:     "promote [flags] [pattern1,pattern2...]",
 		Aliases: []string{"prom", "p"},
 		Short:   "Promote releases across environments",
 		Example: `  # Interactive
@@ -165,15 +217,42 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
   # Multiple releases (comma-separated, preferred)
   joy release promote release-a,release-b --source staging --target production
 
+  # Releases matching globs or /regular expressions/
+  joy release promote 'payments-*,/^worker-(eu|ca)$/' --source staging --target production
+
   # All releases
-  joy release promote --all --source staging --target production`,
+  joy release promote --all --source staging --target production
+
+  # All releases of a release group
+  joy release promote --group checkout --source staging --target production
+
+  # Multiple hops along a path of environments, in a single pull request
+  joy release promote my-release --path staging,demo,production
+
+  # Multiple hops along a path of environments, in a stack of pull requests (one per hop)
+  joy release promote my-release --path staging,demo,production --stacked
+
+  # Structured result for CI, with human-readable progress on stderr
+  joy release promote my-release --source staging --target production --no-prompt --output json`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if output != "text" && output != "json" {
+				return fmt.Errorf("invalid output %q (expecting one of: text, json)", output)
+			}
 			if autoMerge && draft {
 				return fmt.Errorf("flags --auto-merge and --draft cannot be used together")
 			}
+			if len(path) == 1 {
+				return fmt.Errorf("flag --path requires at least 2 environments")
+			}
+			if stacked && len(path) == 0 {
+				return fmt.Errorf("flag --stacked requires --path")
+			}
 			if noPrompt {
-				if len(args) == 0 && !all {
-					return fmt.Errorf("one of releases or --all are required when no-prompt is set")
+				if len(args) == 0 && !all && group == "" && selector == "" {
+					return fmt.Errorf("one of releases, --all, --group or --selector are required when no-prompt is set")
+				}
+				if len(path) > 0 {
+					return nil
 				}
 				if sourceEnv == "" {
 					return fmt.Errorf("source environment is required when no-prompt is set")
@@ -191,20 +270,52 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 			}
 			var expandedReleases []string
 			for _, r := range releases {
-				expandedReleases = append(expandedReleases, strings.Split(r, ",")...)
+				expandedReleases = append(expandedReleases, filtering.SplitNamePatterns(r)...)
 			}
 			releases = expandedReleases
 
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
 
+			// Selectors and groups are matched against releases of the source environment only, keeping target releases
+			// whose labels differ, such that their locked and local values are preserved.
+			filterEnv := sourceEnv
+			if len(path) > 0 {
+				filterEnv = path[0]
+			}
+
 			var filter filtering.Filter
-			if len(releases) == 0 && !all && len(cfg.Releases.Selected) > 0 {
+			if selector != "" {
+				selectorFilter, err := filtering.NewLabelSelectorFilter(selector)
+				if err != nil {
+					return err
+				}
+				cat.WithCrossReleaseFilter(selectorFilter, filterEnv)
+			}
+			if group != "" {
+				groupFilter, err := cat.ReleaseGroupFilter(group)
+				if err != nil {
+					return err
+				}
+				cat.WithCrossReleaseFilter(groupFilter, filterEnv)
+			}
+			if group != "" || selector != "" {
+				// Promote all releases of the group or matching the selector, unless specific releases are requested
+				all = all || len(releases) == 0
+			} else if len(releases) == 0 && !all && len(cfg.Releases.Selected) > 0 {
 				// if there is no pre-selection, ie: user did not explicity pass releases nor use the --all flag
 				// we want to limit the catalog releases to the user config defined release selection.
 				cat.WithReleaseFilter(filtering.NewSpecificReleasesFilter(cfg.Releases.Selected))
 			}
 
+			// Expand release name patterns into the names of matching releases
+			if len(releases) > 0 {
+				var err error
+				if releases, err = cat.ExpandReleasePatterns(releases); err != nil {
+					return err
+				}
+			}
+
 			sourceEnv, err := v1alpha1.GetEnvironmentByName(cat.Environments, sourceEnv)
 			if err != nil {
 				return err
@@ -215,6 +326,15 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 				return err
 			}
 
+			var pathEnvs []*v1alpha1.Environment
+			for _, name := range path {
+				env, err := v1alpha1.GetEnvironmentByName(cat.Environments, name)
+				if err != nil {
+					return err
+				}
+				pathEnvs = append(pathEnvs, env)
+			}
+
 			templateVariables, err := parseTemplateVars(templateVars)
 			if err != nil {
 				return err
@@ -224,17 +344,23 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 
 			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
 
+			// Keep stdout clean for the structured result, reporting progress on stderr instead
+			out := cmd.OutOrStdout()
+			if output == "json" {
+				out = cmd.ErrOrStderr()
+			}
+
 			promoter := promote.Promotion{
 				CommitTemplate:      cfg.Templates.Release.Promote.Commit,
 				PullRequestTemplate: cfg.Templates.Release.Promote.PullRequest,
 				TemplateVariables:   templateVariables,
-				PromptProvider:      cmp.Or[promote.PromptProvider](params.Prompt, promote.NewInteractivePromptProvider(cmd.OutOrStdout())),
+				PromptProvider:      cmp.Or[promote.PromptProvider](params.Prompt, promote.NewInteractivePromptProvider(out)),
 				GitProvider:         cmp.Or[promote.GitProvider](params.Git, promote.NewShellGitProvider(cfg.CatalogDir)),
 				PullRequestProvider: cmp.Or[pr.PullRequestProvider](params.PullRequest, github.NewPullRequestProvider(cfg.CatalogDir)),
 				YamlWriter:          cmp.Or[yml.Writer](params.Writer, yml.DiskWriter),
 				InfoProvider:        cmp.Or(params.Info, infoProvider),
 				LinksProvider:       cmp.Or(params.Links, links.NewProvider(infoProvider, cfg.Templates)),
-				Out:                 cmd.OutOrStdout(),
+				Out:                 out,
 			}
 
 			opts := promote.Opts{
@@ -242,7 +368,7 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 				SourceEnv:            sourceEnv,
 				TargetEnv:            targetEnv,
 				Releases:             releases,
-				ReleasesFiltered:     len(releases) > 0 || filter != nil,
+				ReleasesFiltered:     len(releases) > 0 || filter != nil || group != "" || selector != "",
 				NoPrompt:             noPrompt,
 				AutoMerge:            autoMerge,
 				All:                  all,
@@ -254,10 +380,21 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 				LocalOnly:            localOnly,
 				MaxColumnWidth:       cfg.ColumnWidths.Get(narrow, wide),
 				Reviewers:            reviewers,
+				Path:                 pathEnvs,
+				Stacked:              stacked,
+				OverrideGates:        overrideGates,
+				IncludeDependencies:  withDependencies,
 			}
 
-			_, err = promoter.Promote(opts)
-			return err
+			result, err := promoter.Promote(opts)
+			if err != nil {
+				return err
+			}
+
+			if output == "json" {
+				return formatting.RenderJson(cmd.OutOrStdout(), result)
+			}
+			return nil
 		},
 	}
 
@@ -272,10 +409,19 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 	cmd.Flags().BoolVarP(&narrow, "narrow", "n", false, "Use narrow columns mode")
 	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Use wide columns mode")
 	cmd.Flags().BoolVar(&all, "all", false, "Select all releases")
+	cmd.Flags().StringVarP(&group, "group", "g", "", "Promote releases of given release group (all of them unless releases are specified)")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Promote releases matching label selector (all of them unless releases are specified), e.g. team=fe,tier in (api,worker)")
 	cmd.Flags().BoolVar(&keepPrerelease, "keep-prerelease", false, "Do not promote releases that are prereleases in target env")
 	cmd.Flags().StringSliceVar(&omit, "omit", nil, "Releases to omit from promotion")
+	cmd.Flags().BoolVar(&withDependencies, "with-dependencies", false, "Also promote out of sync releases that selected releases depend on, instead of only warning about them")
 	cmd.Flags().StringSliceVar(&reviewers, "reviewers", nil, "Additional reviewers to add to the PR (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&path, "path", nil, "Comma-separated path of environments to promote through, one hop at a time (e.g. staging,demo,production)")
+	cmd.Flags().BoolVar(&stacked, "stacked", false, "Create a stack of pull requests, one per hop of --path, instead of a single combined one")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format, one of: text, json (json writes a structured result to stdout and progress to stderr)")
+	cmd.Flags().StringSliceVar(&overrideGates, "override-gate", nil, "Promotion gates of target environment to override, recording the override in the PR (flag can be specified multiple times)")
 	cmd.MarkFlagsMutuallyExclusive("narrow", "wide")
+	cmd.MarkFlagsMutuallyExclusive("path", "source")
+	cmd.MarkFlagsMutuallyExclusive("path", "target")
 
 	params.PreRunConfigs.PullCatalog(cmd)
 
@@ -296,17 +442,46 @@ func parseTemplateVars(templateVars []string) (map[string]string, error) {
 
 func NewReleaseSelectCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	allFlag := false
+	var group, selector string
 	cmd := &cobra.Command{
-		Use:     "select",
+		Use:     "select [pattern1,pattern2...]",
 		Aliases: []string{"sel"},
 		Short:   "Select releases to include in listings and promotions",
+		Args:    cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
-			return release.ConfigureSelection(cat, cfg.FilePath, allFlag)
+			if len(args) == 0 && group == "" && selector == "" {
+				return release.ConfigureSelection(cat, cfg.FilePath, allFlag)
+			}
+			if allFlag {
+				return fmt.Errorf("flag --all cannot be combined with release patterns")
+			}
+			if len(args) > 0 {
+				if err := cat.WithReleasePatterns(filtering.SplitNamePatterns(args[0])); err != nil {
+					return err
+				}
+			}
+			if group != "" {
+				if err := cat.WithReleaseGroup(group); err != nil {
+					return err
+				}
+			}
+			if selector != "" {
+				filter, err := filtering.NewLabelSelectorFilter(selector)
+				if err != nil {
+					return err
+				}
+				cat.WithReleaseFilter(filter)
+			}
+			return release.SelectReleases(cfg.FilePath, cat.GetReleaseNames())
 		},
 	}
 	cmd.Flags().BoolVarP(&allFlag, "all", "a", false, "Select all releases (non-interactive)")
+	cmd.Flags().StringVarP(&group, "group", "g", "", "Select releases of given release group (non-interactive)")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Select releases matching label selector (non-interactive), e.g. team=fe,tier in (api,worker)")
+	cmd.MarkFlagsMutuallyExclusive("all", "group")
+	cmd.MarkFlagsMutuallyExclusive("all", "selector")
 
 	preRunConfigs.PullCatalog(cmd)
 
@@ -321,20 +496,31 @@ func NewReleaseRenderCmd() *cobra.Command {
 		colorEnabled bool
 		gitRef       string
 		diffRef      string
+		catalogRef   string
+		diffAgainst  string
 		diffContext  int
 		verbose      bool
 		valuesOnly   bool
 		normalize    bool
 		debug        bool
 		useRawYaml   bool
+		selector     string
+		explain      string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "render [release]",
+		Use:   "render [pattern1,pattern2...]",
 		Short: "render kubernetes manifests from joy release",
 		RunE: func(cmd *cobra.Command, releases []string) (err error) {
 			cfg := config.FromContext(cmd.Context())
 
+			var selectorFilter filtering.Filter
+			if selector != "" {
+				if selectorFilter, err = filtering.NewLabelSelectorFilter(selector); err != nil {
+					return err
+				}
+			}
+
 			uniq := func(values []string) []string {
 				seen := map[string]struct{}{}
 				result := []string{}
@@ -381,6 +567,14 @@ func NewReleaseRenderCmd() *cobra.Command {
 
 			trees := uniq([]string{diffTree, gitTree})
 
+			// The catalog of the working copy is read at the catalog ref instead, if any
+			loadOptions := func(path string) []catalog.LoadOption {
+				if path != cfg.CatalogDir || catalogRef == "" {
+					return nil
+				}
+				return []catalog.LoadOption{catalog.AtRevision(catalogRef)}
+			}
+
 			knownEnvironments, err := func() (envs []string, err error) {
 				for _, path := range trees {
 					// In this case we cannot use the config or catalog loaded from the context
@@ -389,7 +583,7 @@ func NewReleaseRenderCmd() *cobra.Command {
 					if err != nil {
 						return nil, fmt.Errorf("loading config: %w", err)
 					}
-					cat, err := catalog.Load(cmd.Context(), path, cfg.KnownChartRefs())
+					cat, err := catalog.Load(cmd.Context(), path, cfg.KnownChartRefs(), loadOptions(path)...)
 					if err != nil {
 						return nil, fmt.Errorf("loading catalog: %w", err)
 					}
@@ -430,11 +624,12 @@ func NewReleaseRenderCmd() *cobra.Command {
 					if err != nil {
 						return nil, fmt.Errorf("loading config: %w", err)
 					}
-					cat, err := catalog.Load(cmd.Context(), path, cfg.KnownChartRefs())
+					cat, err := catalog.Load(cmd.Context(), path, cfg.KnownChartRefs(), loadOptions(path)...)
 					if err != nil {
 						return nil, fmt.Errorf("loading catalog: %w", err)
 					}
 					cat.WithEnvironments(environments)
+					cat.WithReleaseFilter(selectorFilter)
 					releases = append(releases, cat.GetReleaseNames()...)
 				}
 				return uniq(releases), nil
@@ -443,7 +638,7 @@ func NewReleaseRenderCmd() *cobra.Command {
 				return fmt.Errorf("getting known releases: %w", err)
 			}
 
-			if !all && len(releases) == 0 {
+			if !all && len(releases) == 0 && selector == "" {
 				releases, err = internal.MultiSelect("Which release(s) do you want to render?", knownReleases)
 				if err != nil {
 					return
@@ -453,39 +648,111 @@ func NewReleaseRenderCmd() *cobra.Command {
 				releases = nil
 			}
 
-			for _, releaseItem := range releases {
-				if !slices.Contains(knownReleases, releaseItem) {
-					errs = append(errs, fmt.Errorf("%s", releaseItem))
+			if len(releases) > 0 {
+				var patterns []string
+				for _, releaseItem := range releases {
+					patterns = append(patterns, filtering.SplitNamePatterns(releaseItem)...)
+				}
+				filter, err := filtering.NewNamePatternsFilter(patterns)
+				if err != nil {
+					return err
+				}
+				if releases, err = filter.MatchingNames(knownReleases); err != nil {
+					return err
 				}
 			}
 
-			if err := xerr.MultiErrOrderedFrom("unknown release(s)", errs...); err != nil {
-				return err
+			renderRelease := func(cfg *config.Config, releaseItem *v1alpha1.Release, releaseIdentifier string) (string, error) {
+				pullRenderer, err := helm.NewPullRenderer(cfg.HelmEngine, internal.IoFromCommand(cmd), debug)
+				if err != nil {
+					return "", err
+				}
+
+				cache := newChartCache(cmd, cfg)
+				cache.Puller = pullRenderer
+
+				chart, err := cache.GetReleaseChartFS(cmd.Context(), releaseItem)
+				if err != nil {
+					return "", fmt.Errorf("getting chart for release: %s: %w", releaseIdentifier, err)
+				}
+
+				if explain != "" {
+					release := releaseItem
+					if useRawYaml {
+						if release, err = releaseItem.FromTree(); err != nil {
+							return "", fmt.Errorf("failed to parse release from file tree: %s: %w", releaseIdentifier, err)
+						}
+					}
+					origins, err := render.ExplainValues(release, chart)
+					if err != nil {
+						return "", fmt.Errorf("explaining values of release: %s: %w", releaseIdentifier, err)
+					}
+					return render.FormatValueOrigins(render.FilterValueOrigins(origins, explain), cfg.CatalogDir), nil
+				}
+
+				params := render.RenderParams{
+					Release:    releaseItem,
+					Chart:      chart,
+					Helm:       pullRenderer,
+					ValuesOnly: valuesOnly,
+					UseRawYaml: useRawYaml,
+				}
+
+				result, err := render.Render(cmd.Context(), params)
+				if err != nil {
+					return "", fmt.Errorf("rendering release: %s: %w", releaseIdentifier, err)
+				}
+
+				if normalize {
+					var (
+						builder strings.Builder
+						encoder = yaml.NewEncoder(&builder)
+						decoder = yaml.NewDecoder(strings.NewReader(result))
+					)
+					for {
+						var elem any
+						if err := decoder.Decode(&elem); err != nil {
+							if errors.Is(err, io.EOF) {
+								break
+							}
+							return "", fmt.Errorf("failed to decode values for release: %s: %w", releaseIdentifier, err)
+						}
+						if err := encoder.Encode(elem); err != nil {
+							return "", fmt.Errorf("failed to encode values for release: %s: %w", releaseIdentifier, err)
+						}
+					}
+					result = builder.String()
+				}
+
+				return result, nil
 			}
 
-			render := func(path string) (result map[string]string, err error) {
+			loadTree := func(path string, extraOptions ...catalog.LoadOption) (*config.Config, *catalog.Catalog, error) {
 				// In this case we cannot use the config or catalog loaded from the context
 				// Since we need to reload at whatever git reference we are at.
 				cfg, err := config.Load(cmd.Context(), "", path)
 				if err != nil {
-					return nil, fmt.Errorf("loading config: %w", err)
+					return nil, nil, fmt.Errorf("loading config: %w", err)
 				}
 
-				cat, err := catalog.Load(cmd.Context(), path, cfg.KnownChartRefs())
+				cat, err := catalog.Load(cmd.Context(), path, cfg.KnownChartRefs(), append(loadOptions(path), extraOptions...)...)
 				if err != nil {
-					return nil, fmt.Errorf("loading catalog (%s): %w", path, err)
+					return nil, nil, fmt.Errorf("loading catalog (%s): %w", path, err)
 				}
 
-				cat.WithEnvironments(environments)
-				cat.WithReleases(releases)
+				return cfg, cat, nil
+			}
 
-				cache := helm.ChartCache{
-					Refs:            cfg.Charts,
-					DefaultChartRef: cfg.DefaultChartRef,
-					Root:            cfg.JoyCache,
-					Puller:          helm.CLI{IO: internal.IoFromCommand(cmd)},
+			renderAll := func(path string, extraOptions ...catalog.LoadOption) (result map[string]string, err error) {
+				cfg, cat, err := loadTree(path, extraOptions...)
+				if err != nil {
+					return nil, err
 				}
 
+				cat.WithEnvironments(environments)
+				cat.WithReleases(releases)
+				cat.WithReleaseFilter(selectorFilter)
+
 				results := map[string]string{}
 
 				for i := range cat.Releases.Environments {
@@ -497,43 +764,9 @@ func NewReleaseRenderCmd() *cobra.Command {
 
 						releaseIdentifier := releaseItem.Environment.Name + "/" + releaseItem.Name
 
-						chart, err := cache.GetReleaseChartFS(cmd.Context(), releaseItem)
+						result, err := renderRelease(cfg, releaseItem, releaseIdentifier)
 						if err != nil {
-							return nil, fmt.Errorf("getting chart for release: %s: %w", releaseIdentifier, err)
-						}
-
-						params := render.RenderParams{
-							Release:    releaseItem,
-							Chart:      chart,
-							Helm:       helm.CLI{IO: internal.IoFromCommand(cmd), Debug: debug},
-							ValuesOnly: valuesOnly,
-							UseRawYaml: useRawYaml,
-						}
-
-						result, err := render.Render(cmd.Context(), params)
-						if err != nil {
-							return nil, fmt.Errorf("rendering release: %s: %w", releaseIdentifier, err)
-						}
-
-						if normalize {
-							var (
-								builder strings.Builder
-								encoder = yaml.NewEncoder(&builder)
-								decoder = yaml.NewDecoder(strings.NewReader(result))
-							)
-							for {
-								var elem any
-								if err := decoder.Decode(&elem); err != nil {
-									if errors.Is(err, io.EOF) {
-										break
-									}
-									return nil, fmt.Errorf("failed to decode values for release: %s: %w", releaseIdentifier, err)
-								}
-								if err := encoder.Encode(elem); err != nil {
-									return nil, fmt.Errorf("failed to encode values for release: %s: %w", releaseIdentifier, err)
-								}
-							}
-							result = builder.String()
+							return nil, err
 						}
 
 						results[releaseIdentifier] = result
@@ -579,11 +812,136 @@ func NewReleaseRenderCmd() *cobra.Command {
 				return strings.Join(lines, "\n")
 			}
 
-			gitRefResult, err := render(gitTree)
+			diffFunc := func() text.DiffFunc {
+				if colorEnabled {
+					return text.DiffColorized
+				}
+				return text.Diff
+			}()
+
+			// printResourceDiffs prints the diffs of the resources of each release, by release key
+			printResourceDiffs := func(keys []string, from, to map[string]text.File) error {
+				for _, key := range keys {
+					diffs, err := render.DiffManifests(from[key], to[key], diffContext, diffFunc)
+					if err != nil {
+						return fmt.Errorf("diffing manifests of %s: %w", key, err)
+					}
+
+					var sections []string
+					for _, resource := range diffs {
+						switch {
+						case resource.Diff != "":
+							sections = append(sections, resource.Diff)
+						case verbose:
+							sections = append(sections, fmt.Sprintf("  %s (unchanged)\n", resource.ID))
+						}
+					}
+					if len(sections) == 0 {
+						continue
+					}
+
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n%s\n", header(key), strings.Join(sections, "\n"))
+				}
+				return nil
+			}
+
+			if diffAgainst != "" && slices.Contains(knownEnvironments, diffAgainst) {
+				// Diff against an environment: compare the releases of that environment, as they are and as they would
+				// be once promoted from the selected environments.
+				cfg, cat, err := loadTree(gitTree)
+				if err != nil {
+					return err
+				}
+
+				targetEnv, err := v1alpha1.GetEnvironmentByName(cat.Environments, diffAgainst)
+				if err != nil {
+					return err
+				}
+
+				cat.WithReleases(releases)
+				cat.WithReleaseFilter(selectorFilter)
+
+				sourceEnvs := cat.Environments
+				if len(environments) > 0 {
+					sourceEnvs = nil
+					for _, name := range environments {
+						env, err := v1alpha1.GetEnvironmentByName(cat.Environments, name)
+						if err != nil {
+							return err
+						}
+						sourceEnvs = append(sourceEnvs, env)
+					}
+				}
+
+				var (
+					keys     []string
+					from, to = map[string]text.File{}, map[string]text.File{}
+				)
+				for _, sourceEnv := range sourceEnvs {
+					if sourceEnv.Name == targetEnv.Name {
+						continue
+					}
+
+					list, err := cat.Releases.GetReleasesForPromotion(sourceEnv, targetEnv)
+					if err != nil {
+						return fmt.Errorf("getting releases for promotion from %s to %s: %w", sourceEnv.Name, targetEnv.Name, err)
+					}
+
+					for _, item := range list.SortedCrossReleases() {
+						if item.Releases[0] == nil {
+							continue
+						}
+
+						key := fmt.Sprintf("%s/%s (promoted from %s)", targetEnv.Name, item.Name, sourceEnv.Name)
+						keys = append(keys, key)
+
+						promoted, err := item.PromotedRelease(targetEnv)
+						if err != nil {
+							return fmt.Errorf("getting promoted release %s: %w", item.Name, err)
+						}
+						promotedResult, err := renderRelease(cfg, promoted, key)
+						if err != nil {
+							return err
+						}
+						to[key] = text.File{Name: sourceEnv.Name, Content: promotedResult}
+
+						// Releases not yet in the target environment are diffed against an empty manifest
+						var currentResult string
+						if current := item.Releases[1]; current != nil {
+							if currentResult, err = renderRelease(cfg, current, targetEnv.Name+"/"+item.Name); err != nil {
+								return err
+							}
+						}
+						from[key] = text.File{Name: targetEnv.Name, Content: currentResult}
+					}
+				}
+
+				return printResourceDiffs(keys, from, to)
+			}
+
+			gitRefResult, err := renderAll(gitTree)
 			if err != nil {
 				return err
 			}
 
+			if diffAgainst != "" {
+				// Diff against a git ref: compare the releases as they are at that ref
+				againstResult, err := renderAll(cfg.CatalogDir, catalog.AtRevision(diffAgainst))
+				if err != nil {
+					return err
+				}
+
+				from, to := map[string]text.File{}, map[string]text.File{}
+				for key, result := range againstResult {
+					from[key] = text.File{Name: diffAgainst, Content: result}
+				}
+				for key, result := range gitRefResult {
+					to[key] = text.File{Name: cmp.Or(gitRef, catalogRef, "(current)"), Content: result}
+				}
+
+				return printResourceDiffs(orderedKeys(gitRefResult, againstResult), from, to)
+			}
+
 			if diffRef == "" {
 				for _, key := range orderedKeys(gitRefResult) {
 					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n%s\n\n", header(key), content(gitRefResult[key]))
@@ -591,21 +949,14 @@ func NewReleaseRenderCmd() *cobra.Command {
 				return nil
 			}
 
-			diffRefResult, err := render(diffTree)
+			diffRefResult, err := renderAll(diffTree)
 			if err != nil {
 				return err
 			}
 
-			diffFunc := func() text.DiffFunc {
-				if colorEnabled {
-					return text.DiffColorized
-				}
-				return text.Diff
-			}()
-
 			for _, key := range orderedKeys(gitRefResult, diffRefResult) {
 				diff := diffFunc(
-					text.File{Name: cmp.Or(gitRef, "(current)"), Content: content(gitRefResult[key])},
+					text.File{Name: cmp.Or(gitRef, catalogRef, "(current)"), Content: content(gitRefResult[key])},
 					text.File{Name: diffRef, Content: content(diffRefResult[key])},
 					diffContext,
 				)
@@ -621,29 +972,42 @@ func NewReleaseRenderCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&gitRef, "git-ref", "", "git ref to checkout before render")
 	cmd.Flags().StringVar(&diffRef, "diff-ref", "", "git ref to checkout before render")
+	cmd.Flags().StringVar(&catalogRef, "catalog-ref", "", "git ref to read the catalog from instead of the working copy, without checking it out")
+	cmd.Flags().StringVar(&diffAgainst, "diff-against", "", "environment or git ref to diff rendered resources against, such as the target environment of a promotion")
+	cmd.MarkFlagsMutuallyExclusive("git-ref", "catalog-ref")
+	cmd.MarkFlagsMutuallyExclusive("diff-ref", "diff-against")
 	cmd.Flags().IntVarP(&diffContext, "diff-context", "c", 4, "line context when rendering diff")
 
 	cmd.Flags().StringSliceVarP(&environments, "env", "e", nil, "environments to select releases from.")
 	cmd.Flags().BoolVar(&colorEnabled, "color", term.IsTerminal(int(os.Stdout.Fd())), "toggle output with color")
 	cmd.Flags().BoolVar(&all, "all", false, "select all releases to be rendered")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "select releases matching label selector to be rendered (e.g. team=fe,tier in (api,worker))")
 	cmd.Flags().BoolVar(&allEnvs, "all-envs", false, "select all environments to render from")
 	cmd.Flags().BoolVar(&verbose, "verbose", false, "print empty diffs with headers")
 	cmd.Flags().BoolVar(&valuesOnly, "values", false, "print rendered chart values only")
-	cmd.Flags().BoolVar(&debug, "debug", false, "send the --debug flag to the helm cli")
+	cmd.Flags().BoolVar(&debug, "debug", false, "send the --debug flag to the helm cli, or enable debug output of the helm sdk")
 	cmd.Flags().BoolVar(&normalize, "normalize", false, "decodes and re-encodes the rendered yaml into a normalized format so that templating diffs are ignored")
 	cmd.Flags().BoolVar(&useRawYaml, "raw-yaml", false, "use raw release yaml instead of joy parsed releases for rendering")
+	cmd.Flags().StringVar(&explain, "explain", "", "print chart values with where each of them came from, optionally only those at or below given path (e.g. --explain=image)")
+	cmd.Flags().Lookup("explain").NoOptDefVal = "."
+	cmd.MarkFlagsMutuallyExclusive("explain", "values")
+	cmd.MarkFlagsMutuallyExclusive("explain", "normalize")
+	cmd.MarkFlagsMutuallyExclusive("explain", "diff-against")
+	cmd.MarkFlagsMutuallyExclusive("explain", "diff-ref")
 
 	return cmd
 }
 
-func NewValidateCommand() *cobra.Command {
+func NewValidateCommand(preRunConfigs PreRunConfigs) *cobra.Command {
 	var env string
+	var selector string
 	var noRender bool
 	var noValueTags bool
 	var useRawYaml bool
+	var concurrency int
 
 	cmd := &cobra.Command{
-		Use:   "validate [releases...]",
+		Use:   "validate [pattern1,pattern2...]",
 		Short: "validate releases",
 		Args:  cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -656,16 +1020,22 @@ func NewValidateCommand() *cobra.Command {
 				return strings.Split(env, ",")
 			}()
 
-			releaseFilter := func() filtering.Filter {
-				if len(args) == 0 {
-					return nil
-				}
-				return filtering.NewSpecificReleasesFilter(args)
-			}()
-
 			cat := catalog.FromContext(cmd.Context())
 			cat.WithEnvironments(selectedEnvs)
-			cat.WithReleaseFilter(releaseFilter)
+
+			if len(args) > 0 {
+				if err := cat.WithReleasePatterns(filtering.SplitNamePatterns(args[0])); err != nil {
+					return err
+				}
+			}
+
+			if selector != "" {
+				filter, err := filtering.NewLabelSelectorFilter(selector)
+				if err != nil {
+					return err
+				}
+				cat.WithReleaseFilter(filter)
+			}
 
 			var releases []*v1alpha1.Release
 			for _, item := range cat.Releases.Items {
@@ -677,26 +1047,33 @@ func NewValidateCommand() *cobra.Command {
 				}
 			}
 
+			pullRenderer, err := helm.NewPullRenderer(cfg.HelmEngine, internal.IO{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr(), In: cmd.InOrStdin()}, false)
+			if err != nil {
+				return err
+			}
+
+			cache := newChartCache(cmd, cfg)
+			cache.Puller = pullRenderer
+
 			return validate.Validate(cmd.Context(), validate.ValidateParams{
 				Releases:    releases,
 				NoRender:    noRender,
 				NoValueTags: noValueTags,
 				UseRawYaml:  useRawYaml,
-				Helm:        helm.CLI{IO: internal.IO{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr(), In: cmd.InOrStdin()}},
-				ChartCache: helm.ChartCache{
-					Refs:            cfg.Charts,
-					DefaultChartRef: cfg.DefaultChartRef,
-					Root:            cfg.JoyCache,
-					Puller:          helm.CLI{IO: internal.IO{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr(), In: cmd.InOrStdin()}},
-				},
+				Concurrency: concurrency,
+				Helm:        pullRenderer,
+				ChartCache:  cache,
 			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&env, "env", "e", "", "environment to select release from.")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "validate releases matching label selector (e.g. team=fe,tier in (api,worker))")
 	cmd.Flags().BoolVarP(&noRender, "no-render", "", false, "skips release rendering validation step")
 	cmd.Flags().BoolVarP(&noValueTags, "no-value-tags", "", false, "disallows tags on mapping values")
 	cmd.Flags().BoolVarP(&useRawYaml, "raw-yaml", "", false, "validate against raw yaml release instead of joy parsed release")
+	cmd.Flags().IntVar(&concurrency, "concurrency", runtime.NumCPU(), "maximum number of releases to validate concurrently")
+	addCatalogRefFlag(cmd, preRunConfigs)
 
 	return cmd
 }
@@ -814,7 +1191,7 @@ func NewGitCommands() *cobra.Command {
 	return root
 }
 
-func NewReleaseOpenCmd() *cobra.Command {
+func NewReleaseOpenCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	var env string
 
 	cmd := &cobra.Command{
@@ -861,11 +1238,12 @@ func NewReleaseOpenCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&env, "env", "e", "", "Environment (interactive if not specified)")
+	preRunConfigs.RequireCatalog(cmd, releaseCatalogRequirements(&env))
 
 	return cmd
 }
 
-func NewReleaseLinksCmd() *cobra.Command {
+func NewReleaseLinksCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	var env string
 
 	cmd := &cobra.Command{
@@ -913,6 +1291,7 @@ func NewReleaseLinksCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&env, "env", "e", "", "Environment (interactive if not specified)")
+	preRunConfigs.RequireCatalog(cmd, releaseCatalogRequirements(&env))
 
 	return cmd
 }
@@ -943,13 +1322,14 @@ func NewReleaseSchemaCmd() *cobra.Command {
 
 			cfg := config.FromContext(cmd.Context())
 
-			charts := helm.ChartCache{
-				Refs:            cfg.Charts,
-				DefaultChartRef: cfg.DefaultChartRef,
-				Root:            cfg.JoyCache,
-				Puller:          helm.CLI{IO: internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}},
+			puller, err := helm.NewPullRenderer(cfg.HelmEngine, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}, false)
+			if err != nil {
+				return err
 			}
 
+			charts := newChartCache(cmd, cfg)
+			charts.Puller = puller
+
 			chart, err := charts.GetReleaseChartFS(cmd.Context(), foundRelease)
 			if err != nil {
 				return fmt.Errorf("getting release chart: %w", err)
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nestoca/joy/internal"
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/release/drift"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+func NewReleaseDriftCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var (
+		source, target string
+		noChart        bool
+		narrow, wide   bool
+		format         formatting.Format
+	)
+
+	cmd := &cobra.Command{
+		Use:   "drift --source <env> --target <env>",
+		Short: "Show how releases of target environment would change upon promotion from source environment",
+		Long: `Show how releases of target environment would change upon promotion from source environment.
+
+Compares the fully hydrated values of every release, as they are in the target environment and as they
+would be once promoted from the source environment. Promotion merge semantics apply, so !lock and !local
+values of the target environment are never reported as drift.
+
+By default, release charts are pulled in order to apply their mappings and values schema. Use --no-chart
+to compare the release values alone.`,
+		Example: `  # Show drift between staging and production
+  joy release drift --source staging --target production
+
+  # Output drift as JSON, for instance to post a digest
+  joy release drift -s staging -t production -f json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+
+			params := drift.Params{
+				Catalog: cat,
+				Source:  source,
+				Target:  target,
+			}
+			if !noChart {
+				puller, err := helm.NewPullRenderer(cfg.HelmEngine, internal.IoFromCommand(cmd), false)
+				if err != nil {
+					return err
+				}
+
+				cache := newChartCache(cmd, cfg)
+				cache.Puller = puller
+				params.ChartCache = &cache
+			}
+
+			report, err := drift.Compute(cmd.Context(), params)
+			if err != nil {
+				return err
+			}
+
+			return drift.Render(cmd.OutOrStdout(), report, format, cfg.ColumnWidths.Get(narrow, wide))
+		},
+	}
+
+	cmd.Flags().StringVarP(&source, "source", "s", "", "Source environment")
+	cmd.Flags().StringVarP(&target, "target", "t", "", "Target environment")
+	cmd.Flags().BoolVar(&noChart, "no-chart", false, "Compare release values without applying chart mappings and values schema")
+	cmd.Flags().BoolVarP(&narrow, "narrow", "n", false, "Use narrow columns mode")
+	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Use wide columns mode")
+	cmd.MarkFlagsMutuallyExclusive("narrow", "wide")
+	_ = cmd.MarkFlagRequired("source")
+	_ = cmd.MarkFlagRequired("target")
+	formatting.AddFormatFlag(cmd, &format)
+
+	preRunConfigs.PullCatalog(cmd)
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/release/history"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func NewReleaseHistoryCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var (
+		environments []string
+		narrow, wide bool
+		format       formatting.Format
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history <release> [-e env]",
+		Short: "Show the history of versions of a release across environments",
+		Long: `Show the history of versions of a release across environments, based on the catalog git history.
+
+Each entry is a change of version or chart version of the release, along with the commit that changed it
+and the number of the promotion pull request, when it can be inferred from the commit message.`,
+		Example: `  # History in all environments
+  joy release history my-release
+
+  # History in a given environment, as JSON
+  joy release history my-release -e production -f json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+
+			entries, err := history.Get(history.Params{
+				Catalog:      cat,
+				Provider:     history.NewShellProvider(cfg.CatalogDir),
+				Release:      args[0],
+				Environments: environments,
+			})
+			if err != nil {
+				return err
+			}
+
+			return history.Render(cmd.OutOrStdout(), entries, format, cfg.ColumnWidths.Get(narrow, wide))
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&environments, "env", "e", nil, "Environments to show history from (comma-separated, defaults to all)")
+	cmd.Flags().BoolVarP(&narrow, "narrow", "n", false, "Use narrow columns mode")
+	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Use wide columns mode")
+	cmd.MarkFlagsMutuallyExclusive("narrow", "wide")
+	formatting.AddFormatFlag(cmd, &format)
+
+	preRunConfigs.PullCatalog(cmd)
+
+	return cmd
+}
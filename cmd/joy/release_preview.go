@@ -58,7 +58,7 @@ If the preview already exists, only spec.version is updated.`,
 			cat := catalog.FromContext(cmd.Context())
 			cat.WithEnvironments([]string{env})
 
-			return preview.Create(preview.CreateParams{
+			_, err = preview.Create(preview.CreateParams{
 				Catalog:  cat,
 				Writer:   yml.DiskWriter,
 				Env:      env,
@@ -67,7 +67,9 @@ If the preview already exists, only spec.version is updated.`,
 				Version:  version,
 				Patches:  ops,
 				Replaces: replacements,
+				Out:      cmd.OutOrStdout(),
 			})
+			return err
 		},
 	}
 
@@ -97,12 +99,14 @@ func newReleasePreviewDeleteCmd() *cobra.Command {
 			cat := catalog.FromContext(cmd.Context())
 			cat.WithEnvironments([]string{env})
 
-			return preview.Delete(preview.DeleteParams{
+			_, err := preview.Delete(preview.DeleteParams{
 				Catalog: cat,
 				Env:     env,
 				Release: args[0],
 				Suffix:  suffix,
+				Out:     cmd.OutOrStdout(),
 			})
+			return err
 		},
 	}
 
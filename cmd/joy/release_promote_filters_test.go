@@ -37,6 +37,7 @@ func TestReleasePromoteFilters(t *testing.T) {
 		Name         string
 		Args         []string
 		Releases     []TestCrossRelease
+		Groups       []*v1alpha1.ReleaseGroup
 		Err          string
 		WarnContains string
 		Expectations func(t *testing.T, files []*yml.File)
@@ -127,6 +128,92 @@ func TestReleasePromoteFilters(t *testing.T) {
 			},
 			Err: "selecting releases to promote: release(s) not found: delta",
 		},
+		{
+			Name: "with glob and regex selection",
+			Args: []string{"al*,/^gam{1,2}a$/"},
+			Releases: []TestCrossRelease{
+				{
+					Name:   "alpha",
+					Source: &TestFile{Path: "alpha-source.yaml", Content: "{spec: {version: 1.2.3}}"},
+					Target: &TestFile{Path: "alpha-target.yaml", Content: "{spec: {version: 1.0.0}}"},
+				},
+				{
+					Name:   "beta",
+					Source: &TestFile{Path: "beta-source.yaml", Content: "{spec: {version: 3.2.1}}"},
+					Target: &TestFile{Path: "beta-target.yaml", Content: "{spec: {version: 3.0.0}}"},
+				},
+				{
+					Name:   "gamma",
+					Source: &TestFile{Path: "gamma-source.yaml", Content: "{spec: {version: 2.2.2}}"},
+					Target: &TestFile{Path: "gamma-target.yaml", Content: "{spec: {version: 2.0.0}}"},
+				},
+			},
+			Expectations: func(t *testing.T, files []*yml.File) {
+				require.Len(t, files, 2)
+				require.Equal(t, "alpha-target.yaml", filepath.Base(files[0].Path))
+				require.Equal(t, "gamma-target.yaml", filepath.Base(files[1].Path))
+			},
+		},
+		{
+			Name: "with pattern matching no release",
+			Args: []string{"alpha,delta-*"},
+			Releases: []TestCrossRelease{
+				{
+					Name:   "alpha",
+					Source: &TestFile{Path: "alpha-source.yaml", Content: "{spec: {version: 1.2.3}}"},
+					Target: &TestFile{Path: "alpha-target.yaml", Content: "{spec: {version: 1.0.0}}"},
+				},
+			},
+			Err: "no releases matching: delta-*",
+		},
+		{
+			Name: "selector matches source releases only",
+			Args: []string{"--selector=tier=api"},
+			Releases: []TestCrossRelease{
+				{
+					Name:   "alpha",
+					Source: &TestFile{Path: "alpha-source.yaml", Content: "{metadata: {labels: {tier: api}}, spec: {version: 1.2.3, values: {replicas: 1}}}"},
+					Target: &TestFile{Path: "alpha-target.yaml", Content: "{spec: {version: 1.0.0, values: {replicas: !lock 3}}}"},
+				},
+				{
+					Name:   "beta",
+					Source: &TestFile{Path: "beta-source.yaml", Content: "{metadata: {labels: {tier: worker}}, spec: {version: 3.2.1}}"},
+					Target: &TestFile{Path: "beta-target.yaml", Content: "{metadata: {labels: {tier: api}}, spec: {version: 3.0.0}}"},
+				},
+			},
+			Expectations: func(t *testing.T, files []*yml.File) {
+				require.Len(t, files, 1)
+				require.Equal(t, "alpha-target.yaml", filepath.Base(files[0].Path))
+				require.Equal(t, "{metadata: {labels: {tier: api}}, spec: {version: 1.2.3, values: {replicas: !lock 3}}}\n", string(files[0].MustYaml()))
+			},
+		},
+		{
+			Name: "group matches source releases only",
+			Args: []string{"--group=checkout"},
+			Groups: []*v1alpha1.ReleaseGroup{
+				{
+					ReleaseGroupMetadata: v1alpha1.ReleaseGroupMetadata{ObjectMeta: metav1.ObjectMeta{Name: "checkout"}},
+					Spec:                 v1alpha1.ReleaseGroupSpec{Selectors: []string{"team=checkout"}},
+				},
+			},
+			Releases: []TestCrossRelease{
+				{
+					Name:   "alpha",
+					Source: &TestFile{Path: "alpha-source.yaml", Content: "{metadata: {labels: {team: checkout}}, spec: {version: 1.2.3, values: {replicas: 1}}}"},
+					Target: &TestFile{Path: "alpha-target.yaml", Content: "{spec: {version: 1.0.0, values: {replicas: !lock 3}}}"},
+				},
+				{
+					Name:   "beta",
+					Source: &TestFile{Path: "beta-source.yaml", Content: "{spec: {version: 3.2.1}}"},
+					Target: &TestFile{Path: "beta-target.yaml", Content: "{metadata: {labels: {team: checkout}}, spec: {version: 3.0.0}}"},
+				},
+			},
+			Expectations: func(t *testing.T, files []*yml.File) {
+				require.Len(t, files, 1)
+				require.Equal(t, "alpha-target.yaml", filepath.Base(files[0].Path))
+				require.Equal(t, "{metadata: {labels: {team: checkout}}, spec: {version: 1.2.3, values: {replicas: !lock 3}}}\n", string(files[0].MustYaml()))
+			},
+		},
 		{
 			Name: "all flag selects releases for update",
 			Args: []string{"--all"},
@@ -284,7 +371,8 @@ func TestReleasePromoteFilters(t *testing.T) {
 			envs := []*v1alpha1.Environment{source, target}
 
 			cat := &catalog.Catalog{
-				Environments: envs,
+				Environments:  envs,
+				ReleaseGroups: tc.Groups,
 				Releases: cross.ReleaseList{
 					Environments: envs,
 				},
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/github"
+	"github.com/nestoca/joy/internal/release/history"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/release/rollback"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func NewReleaseRollbackCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var (
+		env                      string
+		toVersion                string
+		draft, dryRun, localOnly bool
+		reviewers                []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rollback -e <env> <release> [--to <version>]",
+		Short: "Roll back a release to a previous version",
+		Long: `Roll back a release of a given environment to a previous version found in the catalog git history.
+
+Only the version (and chart version) of the release file are rewritten, preserving all other values
+and their !lock/!local tags, and a pull request is created for the rollback. The commit message and
+pull request can be customized via the templates.release.promote.rollback catalog config.`,
+		Example: `  # Roll back to the version preceding the current one
+  joy release rollback -e production my-release
+
+  # Roll back to a specific version
+  joy release rollback -e production my-release --to 1.2.3`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+
+			rollbacker := rollback.Rollback{
+				HistoryProvider:     history.NewShellProvider(cfg.CatalogDir),
+				GitProvider:         promote.NewShellGitProvider(cfg.CatalogDir),
+				PullRequestProvider: github.NewPullRequestProvider(cfg.CatalogDir),
+				YamlWriter:          yml.DiskWriter,
+				Template:            cfg.Templates.Release.Promote.Rollback,
+				Out:                 cmd.OutOrStdout(),
+			}
+
+			_, err := rollbacker.Rollback(rollback.Opts{
+				Catalog:     cat,
+				Environment: env,
+				Release:     args[0],
+				ToVersion:   toVersion,
+				Draft:       draft,
+				DryRun:      dryRun,
+				LocalOnly:   localOnly,
+				Reviewers:   reviewers,
+			})
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&env, "env", "e", "", "Environment of the release to roll back")
+	cmd.Flags().StringVar(&toVersion, "to", "", "Version to roll back to (defaults to the version preceding the current one)")
+	cmd.Flags().BoolVar(&draft, "draft", false, "Create draft rollback PR")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Dry run (do not create PR)")
+	cmd.Flags().BoolVar(&localOnly, "local-only", false, "Only update the release file on the local filesystem, without creating branch, commit or PR")
+	cmd.Flags().StringSliceVar(&reviewers, "reviewers", nil, "Additional reviewers to add to the PR (can be specified multiple times)")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "local-only")
+	_ = cmd.MarkFlagRequired("env")
+
+	preRunConfigs.PullCatalog(cmd)
+
+	return cmd
+}
@@ -20,6 +20,7 @@ import (
 
 type PreRunConfig struct {
 	PullCatalog bool
+	Catalog     CatalogRequirements
 }
 
 type PreRunConfigs map[*cobra.Command]PreRunConfig
@@ -30,6 +31,16 @@ func (cfgs PreRunConfigs) PullCatalog(cmd *cobra.Command) {
 	cfgs[cmd] = cfg
 }
 
+func (cfgs PreRunConfigs) SkipCatalog(cmd *cobra.Command) {
+	cfgs.RequireCatalog(cmd, CatalogRequirements{Skip: true})
+}
+
+func (cfgs PreRunConfigs) RequireCatalog(cmd *cobra.Command, requirements CatalogRequirements) {
+	cfg := cfgs[cmd]
+	cfg.Catalog = requirements
+	cfgs[cmd] = cfg
+}
+
 func NewRootCmd(version string, preRunConfigs PreRunConfigs) *cobra.Command {
 	var (
 		configDir        string
@@ -76,6 +87,10 @@ func NewRootCmd(version string, preRunConfigs PreRunConfigs) *cobra.Command {
 				}
 			}
 
+			if flags.Offline {
+				flags.SkipCatalogUpdate = true
+			}
+
 			preRunConfig := preRunConfigs[cmd]
 
 			cfg, err := func() (*config.Config, error) {
@@ -84,7 +99,7 @@ func NewRootCmd(version string, preRunConfigs PreRunConfigs) *cobra.Command {
 					return nil, fmt.Errorf("config not found in context")
 				}
 
-				if preRunConfig.PullCatalog {
+				if preRunConfig.PullCatalog && preRunConfig.Catalog.ref() == "" {
 					if flags.SkipCatalogUpdate {
 						_, _ = fmt.Fprintln(io.Err, "ℹ️ Skipping catalog update.")
 					} else {
@@ -114,11 +129,19 @@ func NewRootCmd(version string, preRunConfigs PreRunConfigs) *cobra.Command {
 
 			cmd.SetContext(config.ToFlagsContext(cmd.Context(), &flags))
 
-			if cmd == setupCmd || cmd == diagnoseCmd || cmd == versionCmd || shouldSkipCatalogLoad(cmd) {
+			if preRunConfig.Catalog.Skip {
 				return nil
 			}
 
-			cat, err := catalog.Load(cmd.Context(), cfg.CatalogDir, cfg.KnownChartRefs())
+			loadOptions := preRunConfig.Catalog.loadOptions(cmd, args)
+			if ref := preRunConfig.Catalog.ref(); ref != "" {
+				loadOptions = append(loadOptions, catalog.AtRevision(ref))
+			}
+			if cfg.CatalogCache {
+				loadOptions = append(loadOptions, catalog.WithCache(cfg.JoyCache))
+			}
+
+			cat, err := catalog.Load(cmd.Context(), cfg.CatalogDir, cfg.KnownChartRefs(), loadOptions...)
 			if err != nil {
 				return fmt.Errorf("loading catalog: %w", err)
 			}
@@ -138,6 +161,8 @@ func NewRootCmd(version string, preRunConfigs PreRunConfigs) *cobra.Command {
 	cmd.PersistentFlags().StringVar(&catalogDir, "catalog-dir", "", "Directory containing joy catalog of environments, projects and releases (defaults to $HOME/.joy)")
 
 	cmd.PersistentFlags().BoolVar(&flags.SkipCatalogUpdate, "skip-catalog-update", false, "Skip catalog update and dirty check")
+	cmd.PersistentFlags().BoolVar(&flags.Offline, "offline", false, "Never pull charts, using only those of the cache or chart mirror (implies --skip-catalog-update)")
+	cmd.PersistentFlags().StringVar(&flags.ChartMirror, "chart-mirror", "", "Directory or bundle of charts to use before pulling charts (overrides chartMirror of .joyrc)")
 
 	// Core commands
 	cmd.AddGroup(&cobra.Group{ID: "core", Title: "Core commands"})
@@ -160,7 +185,13 @@ func NewRootCmd(version string, preRunConfigs PreRunConfigs) *cobra.Command {
 	cmd.AddCommand(setupCmd)
 	cmd.AddCommand(diagnoseCmd)
 	cmd.AddCommand(NewExecuteCmd())
-	cmd.AddCommand(NewCatalogCmd())
+	cmd.AddCommand(NewCatalogCmd(preRunConfigs))
+	cmd.AddCommand(NewCacheCmd(preRunConfigs))
+	cmd.AddCommand(NewChartsCmd(preRunConfigs))
+
+	preRunConfigs.SkipCatalog(setupCmd)
+	preRunConfigs.SkipCatalog(diagnoseCmd)
+	preRunConfigs.SkipCatalog(versionCmd)
 
 	return cmd
 }
@@ -1,7 +1,9 @@
 package build
 
 import (
+	"cmp"
 	"fmt"
+	"io"
 
 	"golang.org/x/mod/semver"
 
@@ -23,13 +25,33 @@ type Opts struct {
 	// ExcludeLabels are `key` or `key=value` selectors; a release carrying any matching
 	// metadata label is skipped. A bare `key` matches the label regardless of its value.
 	ExcludeLabels []string
+
+	// Out receives progress messages (discarded if nil).
+	Out io.Writer
+}
+
+// Result describes the releases of the project promoted to the new version, and those skipped.
+type Result struct {
+	Environment  string           `json:"environment" yaml:"environment"`
+	Project      string           `json:"project" yaml:"project"`
+	Version      string           `json:"version" yaml:"version"`
+	ChartVersion string           `json:"chartVersion,omitempty" yaml:"chartVersion,omitempty"`
+	Promoted     []string         `json:"promoted" yaml:"promoted"`
+	Skipped      []SkippedRelease `json:"skipped" yaml:"skipped"`
+}
+
+type SkippedRelease struct {
+	Name   string `json:"name" yaml:"name"`
+	Reason string `json:"reason" yaml:"reason"`
 }
 
-func Promote(opts Opts) error {
+func Promote(opts Opts) (*Result, error) {
+	out := cmp.Or[io.Writer](opts.Out, io.Discard)
+
 	if !opts.Catalog.Environments[0].Spec.Promotion.FromPullRequests {
 		version := "v" + opts.Version
 		if semver.Prerelease(version)+semver.Build(version) != "" {
-			return fmt.Errorf("cannot promote prerelease version to %s environment", opts.Environment)
+			return nil, fmt.Errorf("cannot promote prerelease version to %s environment", opts.Environment)
 		}
 	}
 
@@ -37,7 +59,7 @@ func Promote(opts Opts) error {
 	// promoting a project's real releases never overwrites the version of a preview copy.
 	excludeSelectors, err := labels.ParseSelectors(append([]string{v1alpha1.PreviewLabel}, opts.ExcludeLabels...))
 	if err != nil {
-		return fmt.Errorf("parsing exclude labels: %w", err)
+		return nil, fmt.Errorf("parsing exclude labels: %w", err)
 	}
 
 	var releases []*v1alpha1.Release
@@ -50,23 +72,33 @@ func Promote(opts Opts) error {
 	}
 
 	if len(releases) == 0 {
-		return fmt.Errorf("no releases found for project %s", opts.Project)
+		return nil, fmt.Errorf("no releases found for project %s", opts.Project)
+	}
+
+	result := &Result{
+		Environment:  opts.Environment,
+		Project:      opts.Project,
+		Version:      opts.Version,
+		ChartVersion: opts.ChartVersion,
+		Promoted:     []string{},
+		Skipped:      []SkippedRelease{},
 	}
 
-	promotionCount := 0
 	for _, release := range releases {
 		if selector, ok := labels.FirstMatch(excludeSelectors, release.Labels); ok {
-			fmt.Printf("⚠️ Skipping promotion of release %s: excluded by label %s\n", style.Resource(release.Name), style.Code(selector.String()))
+			fmt.Fprintf(out, "⚠️ Skipping promotion of release %s: excluded by label %s\n", style.Resource(release.Name), style.Code(selector.String()))
+			result.Skipped = append(result.Skipped, SkippedRelease{Name: release.Name, Reason: "excluded by label " + selector.String()})
 			continue
 		}
 
 		versionKeypair, err := yml.FindNodeKeyPair(release.File.Tree, "spec.version")
 		if err != nil {
-			return fmt.Errorf("release %s has no version property: %w", release.Name, err)
+			return nil, fmt.Errorf("release %s has no version property: %w", release.Name, err)
 		}
 
 		if yml.IsLocked(versionKeypair.Key) || yml.IsLocked(versionKeypair.Value) {
-			fmt.Printf("⚠️ Skipping promotion of release %s: version is locked\n", style.Resource(release.Name))
+			fmt.Fprintf(out, "⚠️ Skipping promotion of release %s: version is locked\n", style.Resource(release.Name))
+			result.Skipped = append(result.Skipped, SkippedRelease{Name: release.Name, Reason: "version is locked"})
 			continue
 		}
 
@@ -75,26 +107,27 @@ func Promote(opts Opts) error {
 		if opts.ChartVersion != "" {
 			chartVersionNode, err := yml.FindNode(release.File.Tree, "spec.chart.version")
 			if err != nil {
-				return fmt.Errorf("release %s has no chart version property: %w", release.Name, err)
+				return nil, fmt.Errorf("release %s has no chart version property: %w", release.Name, err)
 			}
 			chartVersionNode.Value = opts.ChartVersion
 		}
 
 		if err := opts.Writer.WriteFile(release.File); err != nil {
-			return fmt.Errorf("writing release file: %w", err)
+			return nil, fmt.Errorf("writing release file: %w", err)
 		}
 
 		if opts.ChartVersion != "" {
-			fmt.Printf("✅ Promoted release %s to version %s and chart version %s\n", style.Resource(release.Name), style.Version(opts.Version), style.Version(opts.ChartVersion))
+			fmt.Fprintf(out, "✅ Promoted release %s to version %s and chart version %s\n", style.Resource(release.Name), style.Version(opts.Version), style.Version(opts.ChartVersion))
 		} else {
-			fmt.Printf("✅ Promoted release %s to version %s\n", style.Resource(release.Name), style.Version(opts.Version))
+			fmt.Fprintf(out, "✅ Promoted release %s to version %s\n", style.Resource(release.Name), style.Version(opts.Version))
 		}
-		promotionCount++
+		result.Promoted = append(result.Promoted, release.Name)
 	}
 
+	promotionCount := len(result.Promoted)
 	if promotionCount == 0 {
-		fmt.Println("⚠️ No releases were promoted")
-		return nil
+		fmt.Fprintln(out, "⚠️ No releases were promoted")
+		return result, nil
 	}
 
 	plural := ""
@@ -103,10 +136,10 @@ func Promote(opts Opts) error {
 	}
 
 	if opts.ChartVersion != "" {
-		fmt.Printf("🍺 Promoted %d release%s of project %s in environment %s to version %s and chart version %s\n", promotionCount, plural, style.Resource(opts.Project), style.Resource(opts.Environment), style.Version(opts.Version), style.Version(opts.ChartVersion))
+		fmt.Fprintf(out, "🍺 Promoted %d release%s of project %s in environment %s to version %s and chart version %s\n", promotionCount, plural, style.Resource(opts.Project), style.Resource(opts.Environment), style.Version(opts.Version), style.Version(opts.ChartVersion))
 	} else {
-		fmt.Printf("🍺 Promoted %d release%s of project %s in environment %s to version %s\n", promotionCount, plural, style.Resource(opts.Project), style.Resource(opts.Environment), style.Version(opts.Version))
+		fmt.Fprintf(out, "🍺 Promoted %d release%s of project %s in environment %s to version %s\n", promotionCount, plural, style.Resource(opts.Project), style.Resource(opts.Environment), style.Version(opts.Version))
 	}
 
-	return nil
+	return result, nil
 }
@@ -40,7 +40,8 @@ func TestPromote(t *testing.T) {
 		Version:     "1.1.2",
 	}
 
-	require.NoError(t, Promote(opts))
+	_, err := Promote(opts)
+	require.NoError(t, err)
 	require.Len(t, writer.WriteFileCalls(), 1)
 
 	file := writer.WriteFileCalls()[0].File
@@ -75,7 +76,8 @@ func TestPromoteLockedVersion(t *testing.T) {
 		Version:     "1.1.2",
 	}
 
-	require.NoError(t, Promote(opts))
+	_, err := Promote(opts)
+	require.NoError(t, err)
 	require.Len(t, writer.WriteFileCalls(), 0)
 }
 
@@ -88,7 +90,8 @@ func TestPromoteWithPrereleaseVersion(t *testing.T) {
 		Project:     "promote-build",
 		Version:     "1.1.2-updated",
 	}
-	require.EqualError(t, Promote(opts), "cannot promote prerelease version to testing environment")
+	_, err := Promote(opts)
+	require.EqualError(t, err, "cannot promote prerelease version to testing environment")
 }
 
 func TestPromoteWhenNoReleasesFoundForProject(t *testing.T) {
@@ -102,7 +105,8 @@ func TestPromoteWhenNoReleasesFoundForProject(t *testing.T) {
 		Version:     "1.1.2",
 	}
 
-	require.EqualError(t, Promote(opts), "no releases found for project promote-build")
+	_, err := Promote(opts)
+	require.EqualError(t, err, "no releases found for project promote-build")
 }
 
 func TestPromoteExcludesLabeledReleases(t *testing.T) {
@@ -134,7 +138,10 @@ func TestPromoteExcludesLabeledReleases(t *testing.T) {
 		ExcludeLabels: []string{"nesto.ca/preview"},
 	}
 
-	require.NoError(t, Promote(opts))
+	result, err := Promote(opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"regular"}, result.Promoted)
+	require.Equal(t, []SkippedRelease{{Name: "preview", Reason: "excluded by label nesto.ca/preview"}}, result.Skipped)
 	// Only the unlabeled "regular" release is promoted; "preview" is excluded.
 	require.Len(t, writer.WriteFileCalls(), 1)
 	require.Equal(t, "1.1.2", yml.FindNodeValueOrDefault(writer.WriteFileCalls()[0].File.Tree, "spec.version", ""))
@@ -169,7 +176,8 @@ func TestPromoteAutoExcludesPreviewReleases(t *testing.T) {
 		// No ExcludeLabels: the preview label is excluded automatically.
 	}
 
-	require.NoError(t, Promote(opts))
+	_, err := Promote(opts)
+	require.NoError(t, err)
 	require.Len(t, writer.WriteFileCalls(), 1)
 	require.Equal(t, "1.1.2", yml.FindNodeValueOrDefault(writer.WriteFileCalls()[0].File.Tree, "spec.version", ""))
 }
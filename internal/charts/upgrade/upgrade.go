@@ -0,0 +1,361 @@
+// Package upgrade rolls a new version of a catalog chart reference out to environments, by updating the chartVersions
+// entries of environments and the chart versions pinned by releases that resolve to that reference.
+//
+// Versions are rewritten in place, so that all other values and their !lock/!local tags are preserved. Affected releases
+// are rendered with both the current and the new chart version beforehand, such that releases whose values no longer
+// match the schema of the new chart are reported before anything is written.
+package upgrade
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/davidmdm/x/xerr"
+	"github.com/google/uuid"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/git/pr"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/release/render"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/internal/text"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+const defaultTemplate = `Upgrade chart {{ .Ref }} to {{ .ToVersion }} ({{ .Environment.Name }})
+
+Upgrades chart {{ .Ref }} to version {{ .ToVersion }} in environment {{ .Environment.Name }}.
+{{- if .Releases }}
+
+Releases:
+{{- range .Releases }}
+- {{ .Release.Name }}: {{ .FromVersion }} -> {{ $.ToVersion }}
+{{- end }}
+{{- end }}
+`
+
+type Upgrader struct {
+	GitProvider         promote.GitProvider
+	PullRequestProvider pr.PullRequestProvider
+	YamlWriter          yml.Writer
+	ChartCache          helm.ChartCache
+	Helm                helm.PullRenderer
+	Out                 io.Writer
+}
+
+type Opts struct {
+	// Catalog contains the environments and releases to upgrade.
+	Catalog *catalog.Catalog
+
+	// Ref is the catalog chart reference to upgrade.
+	Ref string
+
+	// ToVersion is the chart version to upgrade to.
+	ToVersion string
+
+	// Environments are the names of the environments to upgrade, all of them if empty.
+	Environments []string
+
+	// NoRender skips rendering affected releases before and after the upgrade.
+	NoRender bool
+
+	// PullRequest indicates if a pull request should be created per environment, rather than only writing the changes
+	// to the working tree.
+	PullRequest bool
+
+	// Draft indicates if PRs created need to be draft
+	Draft bool
+
+	// DryRun indicates if the upgrade should be performed in dry-run mode
+	DryRun bool
+
+	// Reviewers are additional reviewers to add to the PRs
+	Reviewers []string
+}
+
+// Info describes the upgrade of an environment and is the data passed to the commit message template.
+type Info struct {
+	Ref         string
+	ToVersion   string
+	Environment *v1alpha1.Environment
+
+	// Releases are the releases whose chart version changes, sorted by name.
+	Releases []ReleaseInfo
+
+	// Files are the files to update: the environment file, if its chartVersions changes, and the files of releases
+	// pinning their chart version.
+	Files []*yml.File
+}
+
+type ReleaseInfo struct {
+	Release     *v1alpha1.Release
+	FromVersion string
+}
+
+// Upgrade upgrades the chart reference in selected environments, returning the URLs of created pull requests, if any.
+func (u *Upgrader) Upgrade(ctx context.Context, opts Opts) ([]string, error) {
+	if _, ok := u.ChartCache.Refs[opts.Ref]; !ok {
+		return nil, fmt.Errorf("unknown chart ref: %s", opts.Ref)
+	}
+
+	var infos []*Info
+	for _, env := range opts.Catalog.Environments {
+		if len(opts.Environments) > 0 && !slices.Contains(opts.Environments, env.Name) {
+			continue
+		}
+
+		info, err := u.plan(opts, env)
+		if err != nil {
+			return nil, fmt.Errorf("planning upgrade of environment %s: %w", env.Name, err)
+		}
+		if len(info.Files) == 0 {
+			u.printf("ℹ️ Environment %s is already up to date\n", style.Resource(env.Name))
+			continue
+		}
+		infos = append(infos, info)
+	}
+
+	if len(infos) == 0 {
+		u.printf("🎉 Chart %s is already at version %s in selected environments\n", style.Resource(opts.Ref), style.Version(opts.ToVersion))
+		return nil, nil
+	}
+
+	if !opts.NoRender {
+		if err := u.checkRenders(ctx, opts, infos); err != nil {
+			return nil, err
+		}
+	}
+
+	var prURLs []string
+	for _, info := range infos {
+		prURL, err := u.apply(opts, info)
+		if err != nil {
+			return prURLs, fmt.Errorf("upgrading environment %s: %w", info.Environment.Name, err)
+		}
+		if prURL != "" {
+			prURLs = append(prURLs, prURL)
+		}
+	}
+
+	if u.ChartCache.Lock != nil && !opts.DryRun {
+		u.printf("🔒 Run %s to record the digest of the new chart version in %s\n", style.Code("joy charts lock"), helm.ChartLockFile)
+	}
+
+	return prURLs, nil
+}
+
+// plan updates the in-memory files of given environment and of its releases resolving to the chart reference.
+func (u *Upgrader) plan(opts Opts, env *v1alpha1.Environment) (*Info, error) {
+	info := &Info{Ref: opts.Ref, ToVersion: opts.ToVersion, Environment: env}
+
+	envIndex := opts.Catalog.Releases.GetEnvironmentIndexByName(env.Name)
+
+	setEnvVersion := false
+	if version, ok := env.Spec.ChartVersions[opts.Ref]; ok && version != opts.ToVersion {
+		setEnvVersion = true
+	}
+
+	for _, item := range opts.Catalog.Releases.Items {
+		if envIndex == -1 {
+			break
+		}
+
+		release := item.Releases[envIndex]
+		if release == nil || release.Spec.Chart.RepoUrl != "" || cmp.Or(release.Spec.Chart.Ref, u.ChartCache.DefaultChartRef) != opts.Ref {
+			continue
+		}
+
+		chart, err := u.ChartCache.GetReleaseChart(release)
+		if err != nil {
+			return nil, fmt.Errorf("getting chart of release %s: %w", release.Name, err)
+		}
+		if chart.Version == opts.ToVersion {
+			continue
+		}
+
+		info.Releases = append(info.Releases, ReleaseInfo{Release: release, FromVersion: chart.Version})
+
+		// Releases without their own chart version follow the version of their environment
+		if release.Spec.Chart.Version == "" {
+			setEnvVersion = true
+			continue
+		}
+
+		if err := yml.SetOrAddNodeValue(release.File.Tree, "spec.chart.version", opts.ToVersion); err != nil {
+			return nil, fmt.Errorf("setting chart version of release %s: %w", release.Name, err)
+		}
+		info.Files = append(info.Files, release.File)
+	}
+
+	if setEnvVersion {
+		path := "spec.chartVersions." + yml.EscapePathSegment(opts.Ref)
+		if err := yml.SetOrAddNodeValue(env.File.Tree, path, opts.ToVersion); err != nil {
+			return nil, fmt.Errorf("setting chart version of environment: %w", err)
+		}
+		info.Files = append([]*yml.File{env.File}, info.Files...)
+	}
+
+	slices.SortFunc(info.Releases, func(a, b ReleaseInfo) int { return strings.Compare(a.Release.Name, b.Release.Name) })
+
+	return info, nil
+}
+
+// checkRenders renders the releases to upgrade with both their current and new chart versions, failing if any of them
+// cannot be rendered with the new version.
+func (u *Upgrader) checkRenders(ctx context.Context, opts Opts, infos []*Info) error {
+	var errs []error
+	for _, info := range infos {
+		for _, releaseInfo := range info.Releases {
+			release := releaseInfo.Release
+			name := info.Environment.Name + "/" + release.Name
+
+			after, err := u.render(ctx, release, opts.ToVersion)
+			if err != nil {
+				u.printf("❌ %s: cannot render with chart version %s\n", style.Resource(name), style.Version(opts.ToVersion))
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				continue
+			}
+
+			before, err := u.render(ctx, release, releaseInfo.FromVersion)
+			if err != nil {
+				u.printf("⚠️ %s: renders with chart version %s, but not with current version %s: %v\n",
+					style.Resource(name), style.Version(opts.ToVersion), style.Version(releaseInfo.FromVersion), err)
+				continue
+			}
+
+			diffs, err := render.DiffManifests(
+				text.File{Name: releaseInfo.FromVersion, Content: before},
+				text.File{Name: opts.ToVersion, Content: after},
+				0,
+				text.Diff,
+			)
+			if err != nil {
+				return fmt.Errorf("%s: diffing manifests: %w", name, err)
+			}
+
+			changed := 0
+			for _, diff := range diffs {
+				if diff.Diff != "" {
+					changed++
+				}
+			}
+			u.printf("✅ %s: %d of %d resource(s) changed by chart version %s\n", style.Resource(name), changed, len(diffs), style.Version(opts.ToVersion))
+		}
+	}
+
+	return xerr.MultiErrOrderedFrom("rendering releases with new chart version (use --no-render to skip)", errs...)
+}
+
+// render renders given release with given version of its chart.
+func (u *Upgrader) render(ctx context.Context, release *v1alpha1.Release, version string) (string, error) {
+	chart, err := u.ChartCache.GetReleaseChart(release)
+	if err != nil {
+		return "", fmt.Errorf("getting chart: %w", err)
+	}
+	if chart.Version != version {
+		chart.Version = version
+		chart.Digest = u.ChartCache.Lock.Digest(chart)
+		chart.ContentDigest = u.ChartCache.Lock.ContentDigest(chart)
+	}
+
+	chartFS, err := u.ChartCache.GetChartFS(ctx, chart)
+	if err != nil {
+		return "", fmt.Errorf("getting chart: %w", err)
+	}
+
+	return render.Render(ctx, render.RenderParams{Release: release, Chart: chartFS, Helm: u.Helm})
+}
+
+// apply writes the files of an environment upgrade and creates its pull request, returning its URL if any.
+func (u *Upgrader) apply(opts Opts, info *Info) (string, error) {
+	u.printf("⬆️ Upgrading chart %s to %s in environment %s\n", style.Resource(info.Ref), style.Version(info.ToVersion), style.Resource(info.Environment.Name))
+
+	var paths []string
+	for _, file := range info.Files {
+		paths = append(paths, file.Path)
+		if opts.DryRun {
+			u.printf("ℹ️ Dry-run: skipping writing file: %s\n", style.SecondaryInfo(file.Path))
+			continue
+		}
+		if err := u.YamlWriter.WriteFile(file); err != nil {
+			return "", fmt.Errorf("writing file %q: %w", file.Path, err)
+		}
+	}
+
+	if !opts.PullRequest {
+		return "", nil
+	}
+
+	message, err := renderMessage(info)
+	if err != nil {
+		return "", err
+	}
+	title, body, _ := strings.Cut(message, "\n")
+
+	branchName := fmt.Sprintf("upgrade-chart-%s-in-%s-to-%s-%s", info.Ref, info.Environment.Name, info.ToVersion, uuid.New().String())
+	labels := []string{"environment:" + info.Environment.Name, "chart:" + info.Ref, "chart-upgrade"}
+
+	if opts.DryRun {
+		u.printf("ℹ️ Dry-run: skipping creation of branch %s and pull request:\n%s\n%s\nLabels:\n%s\n",
+			style.Resource(branchName), style.SecondaryInfo(title), style.SecondaryInfo(body),
+			style.SecondaryInfo("- "+strings.Join(labels, "\n- ")))
+		return "", nil
+	}
+
+	if err := u.GitProvider.CreateAndPushBranchWithFiles(branchName, paths, message); err != nil {
+		return "", err
+	}
+	u.printf("✅ Committed and pushed new branch %s\n", style.Resource(branchName))
+
+	var reviewers []string
+	for _, releaseInfo := range info.Releases {
+		if project := releaseInfo.Release.Project; project != nil {
+			reviewers = promote.MergeUnique(reviewers, project.Spec.Reviewers)
+		}
+	}
+
+	prURL, err := u.PullRequestProvider.Create(pr.CreateParams{
+		Branch:    branchName,
+		Title:     title,
+		Body:      strings.TrimSpace(body),
+		Labels:    labels,
+		Draft:     opts.Draft,
+		Reviewers: promote.MergeUnique(reviewers, opts.Reviewers),
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating pull request: %w", err)
+	}
+	u.printf("✅ Created pull request: %s\n", prURL)
+
+	if err := u.GitProvider.CheckoutMasterBranch(); err != nil {
+		return "", fmt.Errorf("checking out master: %w", err)
+	}
+
+	return prURL, nil
+}
+
+func renderMessage(info *Info) (string, error) {
+	tmpl, err := template.New("message").Funcs(sprig.FuncMap()).Parse(defaultTemplate)
+	if err != nil {
+		return "", fmt.Errorf("parsing upgrade template: %w", err)
+	}
+
+	var message strings.Builder
+	if err := tmpl.Execute(&message, info); err != nil {
+		return "", fmt.Errorf("executing upgrade template: %w", err)
+	}
+
+	return message.String(), nil
+}
+
+func (u *Upgrader) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(u.Out, format, args...)
+}
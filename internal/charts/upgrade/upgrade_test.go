@@ -0,0 +1,254 @@
+package upgrade
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/git/pr"
+	"github.com/nestoca/joy/internal/release/cross"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+const (
+	stagingEnvYAML = `apiVersion: joy.nesto.ca/v1alpha1
+kind: Environment
+metadata:
+  name: staging
+spec:
+  order: 1
+`
+
+	prodEnvYAML = `apiVersion: joy.nesto.ca/v1alpha1
+kind: Environment
+metadata:
+  name: prod
+spec:
+  order: 2
+`
+
+	stagingReleaseYAML = `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.0.0
+  chart:
+    ref: generic
+  values:
+    replicas: 1
+`
+
+	prodReleaseYAML = `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.0.0
+  chart:
+    ref: generic
+    version: 1.0.0
+  values:
+    replicas: !lock 3
+`
+)
+
+func newCatalog(t *testing.T, prodValues string) *catalog.Catalog {
+	newEnv := func(path, content string) *v1alpha1.Environment {
+		file, err := yml.NewFile(path, []byte(content))
+		require.NoError(t, err)
+		env, err := v1alpha1.NewEnvironment(file)
+		require.NoError(t, err)
+		return env
+	}
+
+	newRelease := func(path, content string, env *v1alpha1.Environment) *v1alpha1.Release {
+		file, err := yml.NewFile(path, []byte(content))
+		require.NoError(t, err)
+		release, err := v1alpha1.LoadRelease(file)
+		require.NoError(t, err)
+		release.Environment = env
+		return release
+	}
+
+	staging := newEnv("/catalog/environments/staging/env.yaml", stagingEnvYAML)
+	prod := newEnv("/catalog/environments/prod/env.yaml", prodEnvYAML)
+	environments := []*v1alpha1.Environment{staging, prod}
+
+	return &catalog.Catalog{
+		Environments: environments,
+		Releases: cross.ReleaseList{
+			Environments: environments,
+			Items: []*cross.Release{{
+				Name: "api",
+				Releases: []*v1alpha1.Release{
+					newRelease("/catalog/environments/staging/releases/api.yaml", stagingReleaseYAML, staging),
+					newRelease("/catalog/environments/prod/releases/api.yaml", prodReleaseYAML+prodValues, prod),
+				},
+			}},
+		},
+	}
+}
+
+func newChartCache(t *testing.T) helm.ChartCache {
+	return helm.ChartCache{
+		Refs:            map[string]helm.Chart{"generic": {RepoURL: "ghcr.io/acme/charts", Name: "generic", Version: "1.0.0"}},
+		DefaultChartRef: "generic",
+		Root:            t.TempDir(),
+		Puller: &helm.PullRendererMock{
+			PullFunc: func(ctx context.Context, opts helm.PullOptions) (helm.PullResult, error) {
+				dir := filepath.Join(opts.OutputDir, opts.Chart.Name)
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					return helm.PullResult{}, err
+				}
+				if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("version: "+opts.Chart.Version), 0o644); err != nil {
+					return helm.PullResult{}, err
+				}
+				// The new version of the chart no longer accepts values other than replicas
+				if opts.Chart.Version == "2.0.0" {
+					return helm.PullResult{}, os.WriteFile(filepath.Join(dir, "values.cue"), []byte("#values: close({replicas: int})"), 0o644)
+				}
+				return helm.PullResult{}, nil
+			},
+		},
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	cases := []struct {
+		name          string
+		environments  []string
+		prodValues    string
+		expectedFiles map[string]string
+		expectedPRs   []string
+		expectedErr   string
+	}{
+		{
+			name: "all environments",
+			expectedFiles: map[string]string{
+				"/catalog/environments/staging/env.yaml": `apiVersion: joy.nesto.ca/v1alpha1
+kind: Environment
+metadata:
+  name: staging
+spec:
+  order: 1
+  chartVersions:
+    generic: 2.0.0
+`,
+				"/catalog/environments/prod/releases/api.yaml": `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.0.0
+  chart:
+    ref: generic
+    version: 2.0.0
+  values:
+    replicas: !lock 3
+`,
+			},
+			expectedPRs: []string{
+				"Upgrade chart generic to 2.0.0 (staging)",
+				"Upgrade chart generic to 2.0.0 (prod)",
+			},
+		},
+		{
+			name:         "selected environment",
+			environments: []string{"prod"},
+			expectedFiles: map[string]string{
+				"/catalog/environments/prod/releases/api.yaml": "",
+			},
+			expectedPRs: []string{"Upgrade chart generic to 2.0.0 (prod)"},
+		},
+		{
+			name:        "values not matching new chart schema",
+			prodValues:  "    debug: true\n",
+			expectedErr: "rendering releases with new chart version (use --no-render to skip): prod/api: hydrating values: unifying with chart schema",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			written := map[string]string{}
+
+			gitProvider := &promote.GitProviderMock{}
+			prProvider := &pr.PullRequestProviderMock{
+				CreateFunc: func(params pr.CreateParams) (string, error) {
+					return "https://github.com/acme/catalog/pull/1", nil
+				},
+			}
+			writer := &yml.WriterMock{
+				WriteFileFunc: func(file *yml.File) error {
+					data, err := file.Yaml()
+					written[file.Path] = string(data)
+					return err
+				},
+			}
+
+			upgrader := Upgrader{
+				GitProvider:         gitProvider,
+				PullRequestProvider: prProvider,
+				YamlWriter:          writer,
+				ChartCache:          newChartCache(t),
+				Helm: &helm.PullRendererMock{
+					RenderFunc: func(ctx context.Context, opts helm.RenderOpts) (string, error) {
+						return "kind: Deployment\nmetadata:\n  name: " + opts.ReleaseName + "\n", nil
+					},
+				},
+				Out: io.Discard,
+			}
+
+			prURLs, err := upgrader.Upgrade(context.Background(), Opts{
+				Catalog:      newCatalog(t, tc.prodValues),
+				Ref:          "generic",
+				ToVersion:    "2.0.0",
+				Environments: tc.environments,
+				PullRequest:  true,
+			})
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				require.Empty(t, writer.WriteFileCalls())
+				require.Empty(t, prProvider.CreateCalls())
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, prURLs, len(tc.expectedPRs))
+
+			require.Len(t, written, len(tc.expectedFiles))
+			for path, expected := range tc.expectedFiles {
+				require.Contains(t, written, path)
+				if expected != "" {
+					require.Equal(t, expected, written[path])
+				}
+			}
+
+			var titles []string
+			for _, call := range prProvider.CreateCalls() {
+				titles = append(titles, call.CreateParams.Title)
+				require.Contains(t, call.CreateParams.Labels, "chart-upgrade")
+			}
+			require.Equal(t, tc.expectedPRs, titles)
+
+			require.Len(t, gitProvider.CheckoutMasterBranchCalls(), len(tc.expectedPRs))
+		})
+	}
+}
+
+func TestUpgradeUnknownRef(t *testing.T) {
+	upgrader := Upgrader{ChartCache: newChartCache(t), Out: io.Discard}
+
+	_, err := upgrader.Upgrade(context.Background(), Opts{Catalog: newCatalog(t, ""), Ref: "unknown", ToVersion: "2.0.0"})
+	require.EqualError(t, err, "unknown chart ref: unknown")
+}
@@ -37,6 +37,19 @@ type User struct {
 
 	ColumnWidths ColumnWidths `yaml:"columnWidths,omitempty"`
 
+	// CatalogCache enables an index of catalog files in the joy cache, so that unchanged files are not parsed and
+	// validated again every time the catalog is loaded.
+	CatalogCache bool `yaml:"catalogCache,omitempty"`
+
+	// ChartMirror is a directory with the same layout as the chart cache, or a bundle produced by
+	// "joy cache charts warm --bundle", where charts missing from the cache are looked up before pulling them.
+	ChartMirror string `yaml:"chartMirror,omitempty"`
+
+	// HelmEngine selects how charts are pulled and rendered: "cli" runs the helm binary found on PATH, while "sdk"
+	// uses the Helm Go SDK in-process, for joy builds with the helmsdk build tag.
+	// Optional, defaults to "cli"
+	HelmEngine helm.Engine `yaml:"helmEngine,omitempty"`
+
 	// FilePath is the path to the config file that was loaded, used to write back to the same file.
 	FilePath string `yaml:"-"`
 }
@@ -70,6 +83,9 @@ type Config struct {
 	User
 	Catalog
 
+	// ChartLock is the chart lock of the catalog, if any.
+	ChartLock *helm.ChartLock
+
 	JoyCache string
 }
 
@@ -118,6 +134,10 @@ type ReleaseTemplates struct {
 type ReleasePromoteTemplates struct {
 	Commit      string `yaml:"commit,omitempty"`
 	PullRequest string `yaml:"pullRequest,omitempty"`
+
+	// Rollback is the template for the commit message and pull request of release rollbacks,
+	// where the first line is used as pull request title.
+	Rollback string `yaml:"rollback,omitempty"`
 }
 
 type Help struct {
@@ -207,6 +227,11 @@ func Load(ctx context.Context, configDir, catalogDir string) (*Config, error) {
 		return nil, fmt.Errorf("merging catalog resource into catalog config: %w", err)
 	}
 
+	chartLockPath := filepath.Join(cfg.CatalogDir, helm.ChartLockFile)
+	if cfg.ChartLock, err = helm.LoadChartLock(chartLockPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading chart lock %s: %w", chartLockPath, err)
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
@@ -5,6 +5,12 @@ import "context"
 type GlobalFlags struct {
 	// SkipCatalogUpdate global flag used to skip catalog update and dirty check.
 	SkipCatalogUpdate bool
+
+	// Offline global flag used to never pull charts, nor update the catalog.
+	Offline bool
+
+	// ChartMirror global flag used to override the chart mirror of the user config.
+	ChartMirror string
 }
 
 type flagKey struct{}
@@ -37,7 +37,9 @@ func diagnoseCatalog(ctx context.Context, cfg *config.Config, opts CatalogOpts)
 		opts.CheckCatalog = config.CheckCatalogDir
 	}
 	if opts.LoadCatalog == nil {
-		opts.LoadCatalog = catalog.Load
+		opts.LoadCatalog = func(ctx context.Context, dir string, validChartRefs []string) (*catalog.Catalog, error) {
+			return catalog.Load(ctx, dir, validChartRefs)
+		}
 	}
 	if reflect.ValueOf(opts.Git).IsZero() {
 		opts.Git = GitOpts{
@@ -19,6 +19,7 @@ const (
 	FormatNames    Format = "names"
 	FormatRelPaths Format = "rel-paths"
 	FormatAbsPaths Format = "abs-paths"
+	FormatMarkdown Format = "markdown"
 )
 
 func RenderJson(writer io.Writer, value any) error {
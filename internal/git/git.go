@@ -1,12 +1,16 @@
 package git
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nestoca/survey/v2"
 
@@ -169,6 +173,111 @@ func Add(dir string, files []string) error {
 	return nil
 }
 
+// LogEntry describes a commit of the git log.
+type LogEntry struct {
+	Commit  string
+	Author  string
+	Time    time.Time
+	Message string
+}
+
+// GetFileLog returns the commits that modified given file, from most recent to oldest, excluding those deleting it, as
+// the file has no content as of those.
+func GetFileLog(dir, file string) ([]LogEntry, error) {
+	const (
+		fieldSeparator  = "\x1f"
+		recordSeparator = "\x1e"
+	)
+
+	cmd := exec.Command("git", "-C", dir, "log", "--diff-filter=ACMRT", "--format=%H%x1f%an%x1f%cI%x1f%B%x1e", "--", file)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("getting log of %s: %s", file, string(output))
+	}
+
+	var entries []LogEntry
+	for _, record := range strings.Split(string(output), recordSeparator) {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, fieldSeparator, 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected git log record: %q", record)
+		}
+
+		commitTime, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("parsing time of commit %s: %w", fields[0], err)
+		}
+
+		entries = append(entries, LogEntry{
+			Commit:  fields[0],
+			Author:  fields[1],
+			Time:    commitTime,
+			Message: strings.TrimSpace(fields[3]),
+		})
+	}
+	return entries, nil
+}
+
+// ShowFileRevisions returns the content of given file as of each of given refs, reading them all with a single git
+// process rather than one per ref.
+func ShowFileRevisions(dir, file string, refs []string) ([][]byte, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	if filepath.IsAbs(file) {
+		relativePath, err := filepath.Rel(dir, file)
+		if err != nil {
+			return nil, fmt.Errorf("getting relative path of %s: %w", file, err)
+		}
+		file = relativePath
+	}
+
+	var input strings.Builder
+	for _, ref := range refs {
+		input.WriteString(ref + ":./" + filepath.ToSlash(file) + "\n")
+	}
+
+	cmd := exec.Command("git", "-C", dir, "cat-file", "--batch")
+	cmd.Stdin = strings.NewReader(input.String())
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("showing revisions of %s: %s", file, string(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("showing revisions of %s: %w", file, err)
+	}
+
+	// Each object is output as a "<oid> <type> <size>" header line followed by its content and a newline, or as a
+	// "<object> missing" line if it does not exist.
+	contents := make([][]byte, 0, len(refs))
+	for _, ref := range refs {
+		header, rest, ok := bytes.Cut(output, []byte("\n"))
+		if !ok {
+			return nil, fmt.Errorf("showing %s at %s: unexpected end of output", file, ref)
+		}
+
+		fields := strings.Fields(string(header))
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("showing %s at %s: %s", file, ref, string(header))
+		}
+
+		size, err := strconv.Atoi(fields[2])
+		if err != nil || size+1 > len(rest) {
+			return nil, fmt.Errorf("showing %s at %s: unexpected header: %s", file, ref, string(header))
+		}
+
+		contents = append(contents, rest[:size])
+		output = rest[size+1:]
+	}
+	return contents, nil
+}
+
 func Commit(dir, message string) error {
 	cmd := exec.Command("git", "-C", dir, "commit", "--no-verify", "-m", message)
 	output, err := cmd.CombinedOutput()
@@ -2,6 +2,7 @@ package pr
 
 type CreateParams struct {
 	Branch    string
+	Base      string
 	Title     string
 	Body      string
 	Labels    []string
@@ -73,6 +73,10 @@ func (p *PullRequestProvider) Create(params pr.CreateParams) (string, error) {
 		"--body", params.Body,
 	}
 
+	if params.Base != "" {
+		args = append(args, "--base", params.Base)
+	}
+
 	if params.Draft {
 		args = append(args, "--draft")
 	}
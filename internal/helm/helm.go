@@ -11,6 +11,16 @@ type Chart struct {
 	Name     string         `json:"name" yaml:"name"`
 	Version  string         `json:"version" yaml:"version"`
 	Mappings map[string]any `json:"mappings,omitempty" yaml:"mappings"`
+
+	// Digest optionally pins the chart version to the digest of an OCI chart in its registry, in the form
+	// sha256:<hex>, as reported by helm push, such that pulling a chart overwritten in the registry fails, even if
+	// its version is unchanged.
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+
+	// ContentDigest optionally pins the content of the chart version, such that using a chart whose files differ
+	// fails, including charts modified in the cache since they were pulled. It is a digest of the files of the chart
+	// in the form content-sha256:<hex>, as recorded in charts.lock, and not the digest of the chart in its registry.
+	ContentDigest string `json:"contentDigest,omitempty" yaml:"contentDigest,omitempty"`
 }
 
 func (chart Chart) ToURL() (*url.URL, error) {
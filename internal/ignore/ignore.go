@@ -2,6 +2,7 @@ package ignore
 
 import (
 	"bufio"
+	"io"
 	"os"
 	"path"
 	"strings"
@@ -10,8 +11,8 @@ import (
 )
 
 const (
-	commentPrefix  = "#"
-	ignoreFileName = ".joyignore"
+	commentPrefix = "#"
+	FileName      = ".joyignore"
 )
 
 type Matcher struct {
@@ -23,7 +24,7 @@ func (m *Matcher) Match(path string) bool {
 }
 
 func NewMatcher(catalogRootPath string) (*Matcher, error) {
-	ignoreFilePath := path.Join(catalogRootPath, ignoreFileName)
+	ignoreFilePath := path.Join(catalogRootPath, FileName)
 
 	patterns, err := readIgnoreFile(ignoreFilePath)
 	if err != nil {
@@ -35,6 +36,18 @@ func NewMatcher(catalogRootPath string) (*Matcher, error) {
 	}, nil
 }
 
+// NewMatcherFromReader creates a matcher from the content of a .joyignore file, such as one read from git history.
+func NewMatcherFromReader(reader io.Reader) (*Matcher, error) {
+	patterns, err := parsePatterns(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Matcher{
+		gitignore.NewMatcher(patterns),
+	}, nil
+}
+
 func readIgnoreFile(ignoreFilePath string) (patterns []gitignore.Pattern, err error) {
 	f, err := os.Open(ignoreFilePath)
 	if err != nil {
@@ -43,7 +56,11 @@ func readIgnoreFile(ignoreFilePath string) (patterns []gitignore.Pattern, err er
 
 	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
+	return parsePatterns(f)
+}
+
+func parsePatterns(reader io.Reader) (patterns []gitignore.Pattern, err error) {
+	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		s := scanner.Text()
 		if !strings.HasPrefix(s, commentPrefix) && len(strings.TrimSpace(s)) > 0 {
@@ -51,5 +68,5 @@ func readIgnoreFile(ignoreFilePath string) (patterns []gitignore.Pattern, err er
 		}
 	}
 
-	return patterns, nil
+	return patterns, scanner.Err()
 }
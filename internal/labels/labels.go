@@ -1,52 +1,167 @@
-// Package labels matches Kubernetes-style metadata labels via `key` / `key=value` selectors.
+// Package labels matches Kubernetes-style metadata labels via selectors, such as `key`, `!key`, `key=value`,
+// `key!=value`, `key in (a,b)` and `key notin (a,b)`.
 package labels
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 )
 
-// Selector matches a metadata label by key, and optionally by exact value.
+// Selector matches a metadata label by key, and optionally by exact value or set of values.
 type Selector struct {
 	key      string
 	value    string
 	hasValue bool
+
+	// values is the set of values to match against when inSet is true (`key in (...)`).
+	values []string
+	inSet  bool
+
+	// negated inverts the match (`!key`, `key!=value` and `key notin (...)`), such that a
+	// missing label always matches.
+	negated bool
 }
 
 func (s Selector) String() string {
-	if s.hasValue {
+	switch {
+	case s.inSet && s.negated:
+		return s.key + " notin (" + strings.Join(s.values, ",") + ")"
+	case s.inSet:
+		return s.key + " in (" + strings.Join(s.values, ",") + ")"
+	case s.hasValue && s.negated:
+		return s.key + "!=" + s.value
+	case s.hasValue:
 		return s.key + "=" + s.value
+	case s.negated:
+		return "!" + s.key
+	default:
+		return s.key
 	}
-	return s.key
 }
 
-// matches reports whether labelSet contains this selector's key (and, when a value is
-// specified, that it equals that value).
+// matches reports whether labelSet contains this selector's key (and, when a value or set of
+// values is specified, that it equals that value or one of those values), inverted for negated
+// selectors.
 func (s Selector) matches(labelSet map[string]string) bool {
 	value, ok := labelSet[s.key]
-	if !ok {
-		return false
+	matched := ok
+	if ok && s.inSet {
+		matched = slices.Contains(s.values, value)
+	} else if ok && s.hasValue {
+		matched = value == s.value
 	}
-	return !s.hasValue || value == s.value
+	return matched != s.negated
 }
 
-// ParseSelectors parses `key` / `key=value` specs into selectors. A bare `key` matches the
-// label regardless of its value; `key=value` requires an exact match. An empty key is rejected.
+var setExpr = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+// ParseSelector parses a single selector. A bare `key` matches the label regardless of its value
+// and `!key` matches its absence; `key=value` (or `key==value`) requires an exact match and
+// `key!=value` anything else; `key in (a,b)` requires one of the values and `key notin (a,b)`
+// none of them. Surrounding whitespace is trimmed off keys and values, and an empty key or one
+// containing `=` or `!` is rejected.
+func ParseSelector(spec string) (Selector, error) {
+	trimmed := strings.TrimSpace(spec)
+
+	if match := setExpr.FindStringSubmatch(trimmed); match != nil {
+		if err := validateKey(spec, match[1]); err != nil {
+			return Selector{}, err
+		}
+		selector := Selector{key: match[1], inSet: true, negated: match[2] == "notin"}
+		for _, value := range strings.Split(match[3], ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				selector.values = append(selector.values, value)
+			}
+		}
+		if len(selector.values) == 0 {
+			return Selector{}, fmt.Errorf("invalid label selector %q: empty set of values", spec)
+		}
+		return selector, nil
+	}
+
+	if key, ok := strings.CutPrefix(trimmed, "!"); ok {
+		key = strings.TrimSpace(key)
+		if key == "" || strings.ContainsAny(key, "=!") {
+			return Selector{}, fmt.Errorf("invalid label selector %q: expecting a key after !", spec)
+		}
+		return Selector{key: key, negated: true}, nil
+	}
+
+	var selector Selector
+	if key, value, ok := strings.Cut(spec, "!="); ok {
+		selector = Selector{key: key, value: value, hasValue: true, negated: true}
+	} else if key, value, ok := strings.Cut(spec, "=="); ok {
+		selector = Selector{key: key, value: value, hasValue: true}
+	} else {
+		key, value, hasValue := strings.Cut(spec, "=")
+		selector = Selector{key: key, value: value, hasValue: hasValue}
+	}
+
+	// Trim surrounding whitespace so a padded key or value (e.g. " nesto.ca/preview" or "team = fe")
+	// still matches the actual metadata label rather than silently never matching.
+	selector.key = strings.TrimSpace(selector.key)
+	selector.value = strings.TrimSpace(selector.value)
+	if err := validateKey(spec, selector.key); err != nil {
+		return Selector{}, err
+	}
+	return selector, nil
+}
+
+// validateKey rejects an empty key, or one containing operator characters, such as the `=x` key of
+// `=x!=y`, which could never match a metadata label.
+func validateKey(spec, key string) error {
+	if key == "" {
+		return fmt.Errorf("invalid label selector %q: key is empty", spec)
+	}
+	if strings.ContainsAny(key, "=!") {
+		return fmt.Errorf("invalid label selector %q: key %q contains = or !", spec, key)
+	}
+	return nil
+}
+
+// ParseSelectors parses selector specs, one selector per spec (see ParseSelector).
 func ParseSelectors(specs []string) ([]Selector, error) {
 	selectors := make([]Selector, 0, len(specs))
 	for _, spec := range specs {
-		key, value, hasValue := strings.Cut(spec, "=")
-		// Trim surrounding whitespace so a padded key (e.g. " nesto.ca/preview") still
-		// matches the actual metadata key rather than silently never matching.
-		key = strings.TrimSpace(key)
-		if key == "" {
-			return nil, fmt.Errorf("invalid label selector %q: key is empty", spec)
+		selector, err := ParseSelector(spec)
+		if err != nil {
+			return nil, err
 		}
-		selectors = append(selectors, Selector{key: key, value: value, hasValue: hasValue})
+		selectors = append(selectors, selector)
 	}
 	return selectors, nil
 }
 
+// ParseExpression parses a comma-separated list of selectors, such as `team=fe,tier in (api,worker),!preview`,
+// as accepted by the `--selector` flag of kubectl. Commas within parentheses do not separate selectors.
+func ParseExpression(expr string) ([]Selector, error) {
+	var (
+		specs []string
+		depth int
+		start int
+	)
+	for i, char := range expr {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				specs = append(specs, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid label selector expression %q: unbalanced parentheses", expr)
+	}
+	specs = append(specs, expr[start:])
+	return ParseSelectors(specs)
+}
+
 // FirstMatch returns the first selector matching the given label set, if any.
 func FirstMatch(selectors []Selector, labelSet map[string]string) (Selector, bool) {
 	for _, selector := range selectors {
@@ -56,3 +171,14 @@ func FirstMatch(selectors []Selector, labelSet map[string]string) (Selector, boo
 	}
 	return Selector{}, false
 }
+
+// MatchAll reports whether all selectors match the given label set, as is the case for the selectors of an
+// expression.
+func MatchAll(selectors []Selector, labelSet map[string]string) bool {
+	for _, selector := range selectors {
+		if !selector.matches(labelSet) {
+			return false
+		}
+	}
+	return true
+}
@@ -7,21 +7,26 @@ import (
 )
 
 func TestParseSelectors(t *testing.T) {
-	sels, err := ParseSelectors([]string{"bare", "k=v", "empty=", "  spaced  "})
+	sels, err := ParseSelectors([]string{"bare", "k=v", "empty=", "  spaced  ", " team = fe ", "env != prod"})
 	require.NoError(t, err)
 	require.Equal(t, []Selector{
 		{key: "bare"},
 		{key: "k", value: "v", hasValue: true},
 		{key: "empty", value: "", hasValue: true},
-		{key: "spaced"}, // surrounding whitespace is trimmed off the key
+		{key: "spaced"},                            // surrounding whitespace is trimmed off the key
+		{key: "team", value: "fe", hasValue: true}, // ...and off the value
+		{key: "env", value: "prod", hasValue: true, negated: true},
 	}, sels)
 
+	// A padded value still matches the real metadata value.
+	require.True(t, MatchAll(sels[4:5], map[string]string{"team": "fe"}))
+
 	// A padded key still matches the real metadata key.
 	got, ok := FirstMatch(sels, map[string]string{"spaced": "x"})
 	require.True(t, ok)
 	require.Equal(t, "spaced", got.String())
 
-	for _, bad := range []string{"", "=novalue", "   "} {
+	for _, bad := range []string{"", "=novalue", "   ", " = v", "=x!=y", "a!b=c", "a=b in (x)"} {
 		_, err := ParseSelectors([]string{bad})
 		require.Error(t, err, "selector %q should be rejected", bad)
 	}
@@ -50,3 +55,30 @@ func TestFirstMatch(t *testing.T) {
 	_, ok = FirstMatch(selectors, map[string]string{"unrelated": "x"})
 	require.False(t, ok)
 }
+
+func TestParseExpression(t *testing.T) {
+	selectors, err := ParseExpression("team=fe, tier in (api, worker),!preview,env!=prod,region notin (eu)")
+	require.NoError(t, err)
+
+	var specs []string
+	for _, selector := range selectors {
+		specs = append(specs, selector.String())
+	}
+	require.Equal(t, []string{"team=fe", "tier in (api,worker)", "!preview", "env!=prod", "region notin (eu)"}, specs)
+
+	require.True(t, MatchAll(selectors, map[string]string{"team": "fe", "tier": "api", "env": "staging", "region": "ca"}))
+	require.True(t, MatchAll(selectors, map[string]string{"team": "fe", "tier": "worker"}), "negated selectors match missing labels")
+	require.False(t, MatchAll(selectors, map[string]string{"team": "fe", "tier": "web"}))
+	require.False(t, MatchAll(selectors, map[string]string{"team": "fe", "tier": "api", "preview": "true"}))
+	require.False(t, MatchAll(selectors, map[string]string{"team": "fe", "tier": "api", "env": "prod"}))
+	require.False(t, MatchAll(selectors, map[string]string{"team": "fe", "tier": "api", "region": "eu"}))
+
+	equals, err := ParseExpression("team==fe")
+	require.NoError(t, err)
+	require.Equal(t, []Selector{{key: "team", value: "fe", hasValue: true}}, equals)
+
+	for _, bad := range []string{"tier in ()", "tier in (a", "!", "!key=value", "a,,b"} {
+		_, err := ParseExpression(bad)
+		require.Error(t, err, "expression %q should be rejected", bad)
+	}
+}
@@ -7,7 +7,9 @@ package preview
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -35,6 +37,14 @@ type CreateParams struct {
 	Version  string
 	Patches  []patch.Op
 	Replaces []Replacement
+	Out      io.Writer // receives progress messages (discarded if nil)
+}
+
+// Result describes the preview release written by Create.
+type Result struct {
+	Release string `json:"release" yaml:"release"`
+	File    string `json:"file" yaml:"file"`
+	Version string `json:"version" yaml:"version"`
 }
 
 // DeleteParams are the inputs to Delete.
@@ -43,6 +53,7 @@ type DeleteParams struct {
 	Env     string
 	Release string
 	Suffix  string
+	Out     io.Writer // receives progress messages (discarded if nil)
 }
 
 // Create writes (or, if it already exists, version-bumps) the preview copy of a release.
@@ -50,16 +61,16 @@ type DeleteParams struct {
 // New preview: copy source → built-ins (metadata.name, preview label, version) → patches →
 // replacements → placeholder substitution (__RELEASE__, __SUFFIX__). Existing preview: only
 // spec.version is re-patched (copy is idempotent; other transforms are not re-applied).
-func Create(params CreateParams) error {
+func Create(params CreateParams) (*Result, error) {
 	source, err := findSourceRelease(params.Catalog, params.Release, params.Env)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if params.Suffix == "" {
-		return fmt.Errorf("suffix must not be empty")
+		return nil, fmt.Errorf("suffix must not be empty")
 	}
 	if params.Version == "" {
-		return fmt.Errorf("version must not be empty")
+		return nil, fmt.Errorf("version must not be empty")
 	}
 
 	target := params.Release + params.Suffix
@@ -86,7 +97,7 @@ func Create(params CreateParams) error {
 		return yaml.Marshal(patched)
 	}()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	replacements := append(
@@ -103,43 +114,45 @@ func Create(params CreateParams) error {
 
 	file, err := yml.NewFile(targetPath, text)
 	if err != nil {
-		return fmt.Errorf("reconstructing patched file with text replacements: %w", err)
+		return nil, fmt.Errorf("reconstructing patched file with text replacements: %w", err)
 	}
 	file.Indent = source.File.Indent
 
 	if err := params.Writer.WriteFile(file); err != nil {
-		return fmt.Errorf("writing preview file: %w", err)
+		return nil, fmt.Errorf("writing preview file: %w", err)
 	}
 
-	fmt.Printf("✅ Created preview %s at version %s\n", style.Resource(target), style.Version(params.Version))
-	return nil
+	fmt.Fprintf(cmp.Or[io.Writer](params.Out, io.Discard), "✅ Created preview %s at version %s\n", style.Resource(target), style.Version(params.Version))
+	return &Result{Release: target, File: targetPath, Version: params.Version}, nil
 }
 
-// Delete removes the preview copy of a release, if it exists.
-func Delete(params DeleteParams) error {
+// Delete removes the preview copy of a release, if it exists, and reports whether it was deleted.
+func Delete(params DeleteParams) (bool, error) {
 	source, err := findSourceRelease(params.Catalog, params.Release, params.Env)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if params.Suffix == "" {
-		return fmt.Errorf("suffix must not be empty")
+		return false, fmt.Errorf("suffix must not be empty")
 	}
 
+	out := cmp.Or[io.Writer](params.Out, io.Discard)
+
 	target := params.Release + params.Suffix
 	targetPath := filepath.Join(filepath.Dir(source.File.Path), target+".yaml")
 
 	if _, err := os.Stat(targetPath); err != nil {
 		if os.IsNotExist(err) {
-			fmt.Printf("ℹ️ Preview %s does not exist; nothing to delete\n", style.Resource(target))
-			return nil
+			fmt.Fprintf(out, "ℹ️ Preview %s does not exist; nothing to delete\n", style.Resource(target))
+			return false, nil
 		}
-		return fmt.Errorf("checking preview file %s: %w", targetPath, err)
+		return false, fmt.Errorf("checking preview file %s: %w", targetPath, err)
 	}
 	if err := os.Remove(targetPath); err != nil {
-		return fmt.Errorf("removing preview file %s: %w", targetPath, err)
+		return false, fmt.Errorf("removing preview file %s: %w", targetPath, err)
 	}
-	fmt.Printf("🗑️  Deleted preview %s\n", style.Resource(target))
-	return nil
+	fmt.Fprintf(out, "🗑️  Deleted preview %s\n", style.Resource(target))
+	return true, nil
 }
 
 // findSourceRelease locates the source release within the (single-environment) catalog.
@@ -75,7 +75,7 @@ func decode(t *testing.T, path string) map[string]any {
 
 func TestCreate(t *testing.T) {
 	dir, cat := newCatalog(t)
-	err := Create(CreateParams{
+	result, err := Create(CreateParams{
 		Catalog: cat,
 		Writer:  yml.DiskWriter,
 		Env:     "staging",
@@ -92,6 +92,7 @@ func TestCreate(t *testing.T) {
 		},
 	})
 	require.NoError(t, err)
+	require.Equal(t, &Result{Release: "backoffice-og-1234", File: previewPath(dir), Version: "1.2.3-preview"}, result)
 
 	text, err := os.ReadFile(previewPath(dir))
 	require.NoError(t, err)
@@ -113,32 +114,42 @@ func TestCreate(t *testing.T) {
 
 func TestDelete(t *testing.T) {
 	dir, cat := newCatalog(t)
-	require.NoError(t, Create(CreateParams{
+	_, err := Create(CreateParams{
 		Catalog: cat, Writer: yml.DiskWriter, Env: "staging",
 		Release: "backoffice", Suffix: "-og-1234", Version: "1.0.0",
-	}))
+	})
+	require.NoError(t, err)
 	require.FileExists(t, previewPath(dir))
 
-	require.NoError(t, Delete(DeleteParams{Catalog: cat, Env: "staging", Release: "backoffice", Suffix: "-og-1234"}))
+	deleted, err := Delete(DeleteParams{Catalog: cat, Env: "staging", Release: "backoffice", Suffix: "-og-1234"})
+	require.NoError(t, err)
+	require.True(t, deleted)
 	require.NoFileExists(t, previewPath(dir))
 
 	// Deleting again is a no-op.
-	require.NoError(t, Delete(DeleteParams{Catalog: cat, Env: "staging", Release: "backoffice", Suffix: "-og-1234"}))
+	deleted, err = Delete(DeleteParams{Catalog: cat, Env: "staging", Release: "backoffice", Suffix: "-og-1234"})
+	require.NoError(t, err)
+	require.False(t, deleted)
 }
 
 func TestCreateErrors(t *testing.T) {
 	_, cat := newCatalog(t)
 	base := CreateParams{Catalog: cat, Writer: yml.DiskWriter, Env: "staging", Release: "backoffice", Suffix: "-og-1234", Version: "1.0.0"}
 
+	var err error
+
 	unknown := base
 	unknown.Release = "does-not-exist"
-	require.Error(t, Create(unknown))
+	_, err = Create(unknown)
+	require.Error(t, err)
 
 	noSuffix := base
 	noSuffix.Suffix = ""
-	require.Error(t, Create(noSuffix))
+	_, err = Create(noSuffix)
+	require.Error(t, err)
 
 	noVersion := base
 	noVersion.Version = ""
-	require.Error(t, Create(noVersion))
+	_, err = Create(noVersion)
+	require.Error(t, err)
 }
@@ -68,3 +68,24 @@ func ConfigureSelection(cat *catalog.Catalog, configFilePath string, all bool) e
 	fmt.Println("✅ Config updated.")
 	return nil
 }
+
+// SelectReleases saves given releases as the selection in config file, without prompting user.
+func SelectReleases(configFilePath string, releaseNames []string) error {
+	// Load fresh copy of config file, without any alterations/defaults applied
+	userCfg := config.User{FilePath: configFilePath}
+	if err := config.LoadFile(userCfg.FilePath, &userCfg); err != nil {
+		return fmt.Errorf("loading config file %s: %w", configFilePath, err)
+	}
+
+	if len(releaseNames) == 0 {
+		return fmt.Errorf("no releases to select")
+	}
+
+	userCfg.Releases.Selected = releaseNames
+	if err := userCfg.Save(); err != nil {
+		return fmt.Errorf("saving config file %s: %w", configFilePath, err)
+	}
+
+	fmt.Printf("✅ Selected %d releases.\n", len(releaseNames))
+	return nil
+}
@@ -0,0 +1,88 @@
+package cross
+
+import (
+	"slices"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+// ResolveDependencyRefs resolves the dependencies of each release against the releases of the same environment.
+// Dependencies that cannot be found are skipped, as they are reported by release validation instead of preventing
+// the whole catalog from loading.
+func (r *ReleaseList) ResolveDependencyRefs() {
+	for envIndex := range r.Environments {
+		releases := make(map[string]*v1alpha1.Release)
+		for _, crossRelease := range r.Items {
+			if rel := at(crossRelease.Releases, envIndex); rel != nil {
+				releases[rel.Name] = rel
+			}
+		}
+
+		for _, rel := range releases {
+			rel.Dependencies = nil
+			for _, name := range rel.Spec.DependsOn {
+				if dependency, ok := releases[name]; ok {
+					rel.Dependencies = append(rel.Dependencies, dependency)
+				}
+			}
+		}
+	}
+}
+
+// DependsOn returns the names of releases that this cross-release depends on, as declared by its first release in
+// environment order.
+func (r *Release) DependsOn() []string {
+	for _, rel := range r.Releases {
+		if rel != nil {
+			return rel.Spec.DependsOn
+		}
+	}
+	return nil
+}
+
+// DependencyOrderedCrossReleases returns the cross-releases sorted such that releases come after the releases of
+// the list they depend on, falling back to alphabetical order for independent releases and releases part of a cycle.
+func (r *ReleaseList) DependencyOrderedCrossReleases() []*Release {
+	sorted := r.SortedCrossReleases()
+
+	pending := make(map[string]*Release, len(sorted))
+	for _, release := range sorted {
+		pending[release.Name] = release
+	}
+
+	isReady := func(release *Release) bool {
+		for _, name := range release.DependsOn() {
+			if _, ok := pending[name]; ok && name != release.Name {
+				return false
+			}
+		}
+		return true
+	}
+
+	ordered := make([]*Release, 0, len(sorted))
+	for len(pending) > 0 {
+		index := slices.IndexFunc(sorted, func(release *Release) bool {
+			_, ok := pending[release.Name]
+			return ok && isReady(release)
+		})
+		if index == -1 {
+			// Remaining releases form a cycle, keep them in alphabetical order
+			index = slices.IndexFunc(sorted, func(release *Release) bool {
+				_, ok := pending[release.Name]
+				return ok
+			})
+		}
+		ordered = append(ordered, sorted[index])
+		delete(pending, sorted[index].Name)
+	}
+
+	return ordered
+}
+
+func at[S ~[]E, E any](value S, index int) E {
+	if index < 0 || index >= len(value) {
+		var zero E
+		return zero
+	}
+	return value[index]
+}
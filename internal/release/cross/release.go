@@ -83,6 +83,25 @@ func (r *Release) ComputePromotedFile(sourceEnv, targetEnv *v1alpha1.Environment
 	return nil
 }
 
+// PromotedRelease returns the release as it will exist in the target environment once promoted, assuming the only
+// environments are respectively source and target. If no promotion is needed, the existing target release is returned.
+func (r *Release) PromotedRelease(targetEnv *v1alpha1.Environment) (*v1alpha1.Release, error) {
+	if r.PromotedFile == nil {
+		return r.Releases[1], nil
+	}
+
+	promoted, err := v1alpha1.LoadRelease(r.PromotedFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading promoted release: %w", err)
+	}
+	promoted.Environment = targetEnv
+	if r.Releases[0] != nil {
+		promoted.Project = r.Releases[0].Project
+	}
+
+	return promoted, nil
+}
+
 func NewRelease(name string, environments []*v1alpha1.Environment) *Release {
 	return &Release{
 		Name:     name,
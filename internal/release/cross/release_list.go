@@ -3,6 +3,7 @@ package cross
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
@@ -74,7 +75,7 @@ func LoadReleaseList(allFiles []*yml.File, environments []*v1alpha1.Environment,
 // Release files are assumed to be within the same directory (or any recursive subdirectory) as the environment file.
 func findEnvironmentForReleaseFile(environments []*v1alpha1.Environment, releaseFile *yml.File) *v1alpha1.Environment {
 	for _, env := range environments {
-		if strings.HasPrefix(releaseFile.Path, env.Dir) {
+		if strings.HasPrefix(releaseFile.Path, env.Dir+string(filepath.Separator)) {
 			return env
 		}
 	}
@@ -210,6 +211,48 @@ func (r *ReleaseList) GetReleasesForPromotion(sourceEnv, targetEnv *v1alpha1.Env
 	return subset, nil
 }
 
+// GetReleasesForNextPromotion chains this promotion list onto the next hop of a promotion path. The releases as they
+// will exist in this list's target environment once promoted become the source releases, and their counterparts in
+// the given target environment (looked up in all) become the target releases.
+func (r *ReleaseList) GetReleasesForNextPromotion(all ReleaseList, targetEnv *v1alpha1.Environment) (ReleaseList, error) {
+	if len(r.Environments) != 2 {
+		return ReleaseList{}, fmt.Errorf("expecting 2 environments, got %d", len(r.Environments))
+	}
+
+	sourceEnv := r.Environments[1]
+	targetEnvIndex := all.GetEnvironmentIndexByName(targetEnv.Name)
+	if targetEnvIndex == -1 {
+		return ReleaseList{}, fmt.Errorf("environment %s not found in list", targetEnv.Name)
+	}
+
+	subset := MakeReleaseList([]*v1alpha1.Environment{sourceEnv, targetEnv})
+	for _, item := range r.Items {
+		// Releases missing from the original source do not flow any further along the path
+		var sourceRelease *v1alpha1.Release
+		if item.Releases[0] != nil {
+			var err error
+			sourceRelease, err = item.PromotedRelease(sourceEnv)
+			if err != nil {
+				return ReleaseList{}, fmt.Errorf("getting promoted release %s in environment %s: %w", item.Name, sourceEnv.Name, err)
+			}
+		}
+
+		var targetRelease *v1alpha1.Release
+		if index := all.getReleaseIndex(item.Name); index != -1 {
+			targetRelease = all.Items[index].Releases[targetEnvIndex]
+		}
+
+		newItem := NewRelease(item.Name, subset.Environments)
+		newItem.Releases = []*v1alpha1.Release{sourceRelease, targetRelease}
+
+		if err := newItem.ComputePromotedFile(sourceEnv, targetEnv); err != nil {
+			return ReleaseList{}, fmt.Errorf("computing promoted file for release %s: %w", item.Name, err)
+		}
+		subset.Items = append(subset.Items, newItem)
+	}
+	return subset, nil
+}
+
 // GetNonPromotableReleases returns a list of names of releases that cannot be promoted based
 // on the version format allowed at the target environment.
 func (r *ReleaseList) GetNonPromotableReleases(sourceEnv, targetEnv *v1alpha1.Environment) []string {
@@ -0,0 +1,384 @@
+// Package diff compares the releases of two catalogs, typically loaded at two git revisions, to report which releases
+// of each environment were added, removed or changed, such as when reviewing a promotion pull request.
+//
+// Value changes are annotated when the value is locked (!lock) in either catalog, as locked values are never
+// overwritten by promotion and changing them is therefore worth a closer look.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/release/drift"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+type ChangeType = drift.ChangeType
+
+const (
+	ChangeAdded   = drift.ChangeAdded
+	ChangeChanged = drift.ChangeChanged
+	ChangeRemoved = drift.ChangeRemoved
+)
+
+// ValueChange describes a value of a release that changed between both catalogs.
+type ValueChange struct {
+	drift.Change `yaml:",inline"`
+
+	// Locked is whether the value is locked (!lock) in either catalog.
+	Locked bool `json:"locked,omitempty" yaml:"locked,omitempty"`
+}
+
+// Release describes how a release of an environment changed between both catalogs.
+type Release struct {
+	Name   string     `json:"name" yaml:"name"`
+	Change ChangeType `json:"change" yaml:"change"`
+
+	// FromVersion and FromChart are empty for added releases, while ToVersion and ToChart are empty for removed ones.
+	FromVersion string `json:"fromVersion,omitempty" yaml:"fromVersion,omitempty"`
+	ToVersion   string `json:"toVersion,omitempty" yaml:"toVersion,omitempty"`
+	FromChart   string `json:"fromChart,omitempty" yaml:"fromChart,omitempty"`
+	ToChart     string `json:"toChart,omitempty" yaml:"toChart,omitempty"`
+
+	// Values are the changes to the values of changed releases, sorted by path.
+	Values []ValueChange `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+// Environment describes the releases of an environment that changed between both catalogs.
+type Environment struct {
+	Name string `json:"name" yaml:"name"`
+
+	// Releases are the releases that changed, sorted by name.
+	Releases []Release `json:"releases" yaml:"releases"`
+}
+
+// Report describes the changes from a base catalog to a head catalog.
+type Report struct {
+	Base string `json:"base" yaml:"base"`
+	Head string `json:"head" yaml:"head"`
+
+	// Environments are the environments with changed releases, in the order of the head catalog, followed by those
+	// only found in the base catalog.
+	Environments []Environment `json:"environments" yaml:"environments"`
+}
+
+type Params struct {
+	Base    *catalog.Catalog
+	BaseRef string
+	Head    *catalog.Catalog
+	HeadRef string
+}
+
+// Compute returns the changes of releases from the base catalog to the head catalog.
+func Compute(params Params) *Report {
+	report := &Report{
+		Base:         params.BaseRef,
+		Head:         params.HeadRef,
+		Environments: []Environment{},
+	}
+
+	var names []string
+	for _, env := range params.Head.Environments {
+		names = append(names, env.Name)
+	}
+	for _, env := range params.Base.Environments {
+		if !slices.Contains(names, env.Name) {
+			names = append(names, env.Name)
+		}
+	}
+
+	for _, name := range names {
+		baseReleases := releasesOfEnvironment(params.Base, name)
+		headReleases := releasesOfEnvironment(params.Head, name)
+
+		env := Environment{Name: name, Releases: []Release{}}
+		for _, releaseName := range releaseNames(baseReleases, headReleases) {
+			if release, changed := compareReleases(releaseName, baseReleases[releaseName], headReleases[releaseName]); changed {
+				env.Releases = append(env.Releases, release)
+			}
+		}
+
+		if len(env.Releases) > 0 {
+			report.Environments = append(report.Environments, env)
+		}
+	}
+
+	return report
+}
+
+func releasesOfEnvironment(cat *catalog.Catalog, name string) map[string]*v1alpha1.Release {
+	releases := map[string]*v1alpha1.Release{}
+
+	index := cat.Releases.GetEnvironmentIndexByName(name)
+	if index == -1 {
+		return releases
+	}
+
+	for _, item := range cat.Releases.Items {
+		if release := item.Releases[index]; release != nil {
+			releases[item.Name] = release
+		}
+	}
+	return releases
+}
+
+func releaseNames(base, head map[string]*v1alpha1.Release) []string {
+	var names []string
+	for name := range base {
+		names = append(names, name)
+	}
+	for name := range head {
+		if _, ok := base[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func compareReleases(name string, base, head *v1alpha1.Release) (Release, bool) {
+	release := Release{Name: name}
+
+	switch {
+	case base == nil:
+		release.Change = ChangeAdded
+		release.ToVersion = head.Spec.Version
+		release.ToChart = chartString(head.Spec.Chart)
+		return release, true
+	case head == nil:
+		release.Change = ChangeRemoved
+		release.FromVersion = base.Spec.Version
+		release.FromChart = chartString(base.Spec.Chart)
+		return release, true
+	}
+
+	release.Change = ChangeChanged
+	release.FromVersion = base.Spec.Version
+	release.ToVersion = head.Spec.Version
+	release.FromChart = chartString(base.Spec.Chart)
+	release.ToChart = chartString(head.Spec.Chart)
+
+	locked := append(lockedPaths(base.File), lockedPaths(head.File)...)
+	for _, change := range drift.DiffValues(base.Spec.Values, head.Spec.Values) {
+		release.Values = append(release.Values, ValueChange{Change: change, Locked: isLocked(change.Path, locked)})
+	}
+
+	changed := release.FromVersion != release.ToVersion ||
+		!reflect.DeepEqual(base.Spec.Chart, head.Spec.Chart) ||
+		len(release.Values) > 0
+
+	return release, changed
+}
+
+// chartString returns a short human-readable identifier of the chart of a release.
+func chartString(chart v1alpha1.ReleaseChart) string {
+	var name string
+	switch {
+	case chart.Ref != "":
+		name = chart.Ref
+	case chart.RepoUrl != "":
+		name = strings.TrimSuffix(chart.RepoUrl, "/") + "/" + chart.Name
+	default:
+		name = "(default)"
+	}
+
+	if chart.Version != "" {
+		name += "@" + chart.Version
+	}
+	return name
+}
+
+// lockedPaths returns the dotted paths of the values that are locked in given release file, in the same format as
+// the paths of value changes. Sequences containing locked values are considered locked as a whole, as they are
+// compared as a whole.
+func lockedPaths(file *yml.File) []string {
+	if file == nil {
+		return nil
+	}
+
+	values, err := yml.FindNode(file.Tree, "spec.values")
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	collectLockedPaths(values, "", &paths)
+	return paths
+}
+
+func collectLockedPaths(node *yaml.Node, path string, paths *[]string) {
+	if yml.IsLocked(node) {
+		*paths = append(*paths, path)
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+
+			if yml.IsLocked(key) {
+				*paths = append(*paths, childPath)
+				continue
+			}
+			collectLockedPaths(value, childPath, paths)
+		}
+	case yaml.SequenceNode:
+		var nested []string
+		for _, item := range node.Content {
+			collectLockedPaths(item, path+"[]", &nested)
+		}
+		if len(nested) > 0 {
+			*paths = append(*paths, path)
+		}
+	}
+}
+
+// isLocked returns whether the value at given path is locked, itself or through any of its parents, including the
+// values as a whole (empty locked path).
+func isLocked(path string, locked []string) bool {
+	return slices.ContainsFunc(locked, func(lockedPath string) bool {
+		return lockedPath == "" || path == lockedPath || strings.HasPrefix(path, lockedPath+".")
+	})
+}
+
+func Render(writer io.Writer, report *Report, format formatting.Format) error {
+	switch format {
+	case formatting.FormatJson:
+		return formatting.RenderJson(writer, report)
+	case formatting.FormatYaml:
+		return formatting.RenderYaml(writer, report)
+	case formatting.FormatTable:
+		return renderTable(writer, report)
+	case formatting.FormatMarkdown:
+		return renderMarkdown(writer, report)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func renderTable(writer io.Writer, report *Report) error {
+	if len(report.Environments) == 0 {
+		_, err := fmt.Fprintf(writer, "🎉 No release changes from %s to %s\n", style.Resource(report.Base), style.Resource(report.Head))
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"ENVIRONMENT", "RELEASE", "CHANGE", "VERSION", "CHART", "VALUES"})
+
+	count := 0
+	for _, env := range report.Environments {
+		for _, release := range env.Releases {
+			t.AppendRow(table.Row{
+				env.Name,
+				release.Name,
+				release.Change,
+				transition(release.FromVersion, release.ToVersion, style.Version),
+				transition(release.FromChart, release.ToChart, identity),
+				strings.Join(valueLines(release.Values), "\n"),
+			})
+			t.AppendSeparator()
+			count++
+		}
+	}
+
+	if _, err := io.WriteString(writer, t.Render()+"\n"); err != nil {
+		return fmt.Errorf("writing diff as table: %w", err)
+	}
+
+	_, err := fmt.Fprintf(writer, "%d release change(s) across %d environment(s) from %s to %s\n",
+		count, len(report.Environments), style.Resource(report.Base), style.Resource(report.Head))
+	return err
+}
+
+func renderMarkdown(writer io.Writer, report *Report) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "## Catalog changes from `%s` to `%s`\n", report.Base, report.Head)
+
+	if len(report.Environments) == 0 {
+		builder.WriteString("\nNo release changes.\n")
+	}
+
+	for _, env := range report.Environments {
+		t := table.NewWriter()
+		t.AppendHeader(table.Row{"Release", "Change", "Version", "Chart", "Values"})
+		for _, release := range env.Releases {
+			var values []string
+			for _, change := range release.Values {
+				value := "`" + changePrefix(change.Type) + " " + change.Path + "`"
+				if change.Locked {
+					value += " 🔒 **locked**"
+				}
+				values = append(values, value)
+			}
+			t.AppendRow(table.Row{
+				release.Name,
+				release.Change,
+				transition(release.FromVersion, release.ToVersion, identity),
+				transition(release.FromChart, release.ToChart, identity),
+				strings.Join(values, "<br>"),
+			})
+		}
+		fmt.Fprintf(&builder, "\n### %s\n\n%s\n", env.Name, t.RenderMarkdown())
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+// valueLines describes value changes one per line, such as `~ image.tag`, annotating locked values.
+func valueLines(changes []ValueChange) []string {
+	var lines []string
+	for _, change := range changes {
+		line := changePrefix(change.Type) + " " + change.Path
+		if change.Locked {
+			line += " 🔒"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func transition(from, to string, format func(any) string) string {
+	switch {
+	case from == to:
+		return format(to)
+	case from == "":
+		return "(new) -> " + format(to)
+	case to == "":
+		return format(from) + " -> (removed)"
+	default:
+		return format(from) + " -> " + format(to)
+	}
+}
+
+func identity(value any) string {
+	return fmt.Sprint(value)
+}
+
+func changePrefix(changeType ChangeType) string {
+	switch changeType {
+	case ChangeAdded:
+		return "+"
+	case ChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
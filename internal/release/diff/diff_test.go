@@ -0,0 +1,121 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/release/drift"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func loadCatalog(t *testing.T, releases map[string]string) *catalog.Catalog {
+	t.Helper()
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"projects/api.yaml": `apiVersion: joy.nesto.ca/v1alpha1
+kind: Project
+metadata:
+  name: api
+spec: {}
+`,
+		"environments/prod/env.yaml": `apiVersion: joy.nesto.ca/v1alpha1
+kind: Environment
+metadata:
+  name: prod
+spec: {}
+`,
+	}
+	for name, content := range releases {
+		files["environments/prod/releases/"+name+".yaml"] = content
+	}
+
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	cat, err := catalog.Load(context.Background(), dir, nil)
+	require.NoError(t, err)
+	return cat
+}
+
+func release(name, version, values string) string {
+	return `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: ` + name + `
+spec:
+  project: api
+  version: ` + version + `
+  chart:
+    repoUrl: acme.com/charts
+    name: generic
+    version: 1.0.0
+  values:
+` + values
+}
+
+func TestCompute(t *testing.T) {
+	base := loadCatalog(t, map[string]string{
+		"api":     release("api", "1.0.0", "    replicas: !lock 3\n    image:\n      tag: a\n"),
+		"worker":  release("worker", "1.0.0", "    replicas: 1\n"),
+		"removed": release("removed", "1.0.0", "    replicas: 1\n"),
+	})
+	head := loadCatalog(t, map[string]string{
+		"api":    release("api", "1.1.0", "    replicas: !lock 4\n    image:\n      tag: b\n"),
+		"worker": release("worker", "1.0.0", "    replicas: 1\n"),
+		"added":  release("added", "2.0.0", "    replicas: 1\n"),
+	})
+
+	report := Compute(Params{Base: base, BaseRef: "master", Head: head, HeadRef: "pr"})
+
+	require.Equal(t, &Report{
+		Base: "master",
+		Head: "pr",
+		Environments: []Environment{
+			{
+				Name: "prod",
+				Releases: []Release{
+					{Name: "added", Change: ChangeAdded, ToVersion: "2.0.0", ToChart: "acme.com/charts/generic@1.0.0"},
+					{
+						Name:        "api",
+						Change:      ChangeChanged,
+						FromVersion: "1.0.0",
+						ToVersion:   "1.1.0",
+						FromChart:   "acme.com/charts/generic@1.0.0",
+						ToChart:     "acme.com/charts/generic@1.0.0",
+						Values: []ValueChange{
+							{Change: drift.Change{Path: "image.tag", Type: ChangeChanged, From: "a", To: "b"}},
+							{Change: drift.Change{Path: "replicas", Type: ChangeChanged, From: float64(3), To: float64(4)}, Locked: true},
+						},
+					},
+					{Name: "removed", Change: ChangeRemoved, FromVersion: "1.0.0", FromChart: "acme.com/charts/generic@1.0.0"},
+				},
+			},
+		},
+	}, report)
+
+	var markdown bytes.Buffer
+	require.NoError(t, Render(&markdown, report, formatting.FormatMarkdown))
+	require.Contains(t, markdown.String(), "### prod")
+	require.Contains(t, markdown.String(), "`~ replicas` 🔒 **locked**")
+
+	var table bytes.Buffer
+	require.NoError(t, Render(&table, report, formatting.FormatTable))
+	require.Contains(t, table.String(), "3 release change(s) across 1 environment(s)")
+}
+
+func TestComputeWithoutChanges(t *testing.T) {
+	cat := loadCatalog(t, map[string]string{"api": release("api", "1.0.0", "    replicas: 1\n")})
+
+	report := Compute(Params{Base: cat, BaseRef: "master", Head: cat, HeadRef: "pr"})
+	require.Empty(t, report.Environments)
+}
@@ -0,0 +1,282 @@
+// Package drift compares the fully hydrated values of releases between a source and a target environment, to report
+// what would change in the target environment if all releases were promoted from the source environment.
+//
+// Promoted releases are computed with the same merge semantics as promotion, so that !lock and !local values of the
+// target environment, which promotion never overwrites, are not reported as drift.
+package drift
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/release/render"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/internal/text"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeChanged ChangeType = "changed"
+	ChangeRemoved ChangeType = "removed"
+)
+
+// Change describes a value that would change in the target environment upon promotion.
+type Change struct {
+	Path string     `json:"path" yaml:"path"`
+	Type ChangeType `json:"type" yaml:"type"`
+	From any        `json:"from,omitempty" yaml:"from,omitempty"`
+	To   any        `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// Release describes how a release of the target environment would change upon promotion.
+type Release struct {
+	Name string `json:"name" yaml:"name"`
+
+	// FromVersion is the current version in target environment, empty if release does not exist there yet.
+	FromVersion string `json:"fromVersion,omitempty" yaml:"fromVersion,omitempty"`
+	ToVersion   string `json:"toVersion" yaml:"toVersion"`
+
+	Added   int `json:"added" yaml:"added"`
+	Changed int `json:"changed" yaml:"changed"`
+	Removed int `json:"removed" yaml:"removed"`
+
+	Changes []Change `json:"changes" yaml:"changes"`
+}
+
+// Report describes the drift of all releases between source and target environments.
+type Report struct {
+	Source string `json:"source" yaml:"source"`
+	Target string `json:"target" yaml:"target"`
+
+	// Releases are the releases that would change upon promotion, sorted by name.
+	Releases []Release `json:"releases" yaml:"releases"`
+
+	// InSync are the names of releases that would not change upon promotion, sorted by name.
+	InSync []string `json:"inSync" yaml:"inSync"`
+}
+
+type Params struct {
+	Catalog *catalog.Catalog
+	Source  string
+	Target  string
+
+	// ChartCache is used to resolve release charts, so that chart mappings and values schema are applied to the
+	// hydrated values. If nil, values are hydrated without their chart.
+	ChartCache *helm.ChartCache
+}
+
+// Compute returns the drift report of releases between source and target environments.
+func Compute(ctx context.Context, params Params) (*Report, error) {
+	sourceEnv, err := v1alpha1.GetEnvironmentByName(params.Catalog.Environments, params.Source)
+	if err != nil {
+		return nil, fmt.Errorf("getting source environment: %w", err)
+	}
+	targetEnv, err := v1alpha1.GetEnvironmentByName(params.Catalog.Environments, params.Target)
+	if err != nil {
+		return nil, fmt.Errorf("getting target environment: %w", err)
+	}
+	if sourceEnv == nil || targetEnv == nil {
+		return nil, fmt.Errorf("both source and target environments are required")
+	}
+	if sourceEnv.Name == targetEnv.Name {
+		return nil, fmt.Errorf("source and target environments must be different")
+	}
+
+	list, err := params.Catalog.Releases.GetReleasesForPromotion(sourceEnv, targetEnv)
+	if err != nil {
+		return nil, fmt.Errorf("getting releases for promotion: %w", err)
+	}
+
+	report := &Report{
+		Source:   sourceEnv.Name,
+		Target:   targetEnv.Name,
+		Releases: []Release{},
+		InSync:   []string{},
+	}
+
+	for _, item := range list.SortedCrossReleases() {
+		if item.Releases[0] == nil {
+			continue
+		}
+		if item.PromotedFile == nil {
+			report.InSync = append(report.InSync, item.Name)
+			continue
+		}
+
+		promoted, err := item.PromotedRelease(targetEnv)
+		if err != nil {
+			return nil, fmt.Errorf("getting promoted release %s: %w", item.Name, err)
+		}
+
+		toValues, err := hydrateValues(ctx, params.ChartCache, promoted)
+		if err != nil {
+			return nil, fmt.Errorf("hydrating values of promoted release %s: %w", item.Name, err)
+		}
+
+		release := Release{
+			Name:      item.Name,
+			ToVersion: promoted.Spec.Version,
+		}
+
+		var fromValues map[string]any
+		if current := item.Releases[1]; current != nil {
+			release.FromVersion = current.Spec.Version
+			fromValues, err = hydrateValues(ctx, params.ChartCache, current)
+			if err != nil {
+				return nil, fmt.Errorf("hydrating values of release %s in environment %s: %w", item.Name, targetEnv.Name, err)
+			}
+		}
+
+		release.Changes = DiffValues(fromValues, toValues)
+		for _, change := range release.Changes {
+			switch change.Type {
+			case ChangeAdded:
+				release.Added++
+			case ChangeChanged:
+				release.Changed++
+			case ChangeRemoved:
+				release.Removed++
+			}
+		}
+
+		report.Releases = append(report.Releases, release)
+	}
+
+	return report, nil
+}
+
+func hydrateValues(ctx context.Context, cache *helm.ChartCache, release *v1alpha1.Release) (map[string]any, error) {
+	var chart *helm.ChartFS
+	if cache != nil {
+		var err error
+		chart, err = cache.GetReleaseChartFS(ctx, release)
+		if err != nil {
+			return nil, fmt.Errorf("getting release chart: %w", err)
+		}
+	}
+	return render.HydrateValues(release, chart)
+}
+
+// DiffValues returns the changes from one set of values to another, sorted by path. Mappings are compared key by key
+// down to their leaves, while any other values, including sequences, are compared as a whole.
+func DiffValues(from, to map[string]any) []Change {
+	changes := []Change{}
+	diffMaps("", from, to, &changes)
+	return changes
+}
+
+func diffMaps(prefix string, from, to map[string]any, changes *[]Change) {
+	keys := slices.Collect(maps.Keys(from))
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+		fromMap, fromIsMap := fromValue.(map[string]any)
+		toMap, toIsMap := toValue.(map[string]any)
+
+		// Descend into mappings that are added, removed or present on both sides, so that changes are counted by leaf
+		switch {
+		case (fromIsMap || !inFrom) && (toIsMap || !inTo):
+			diffMaps(path, fromMap, toMap, changes)
+		case !inFrom:
+			*changes = append(*changes, Change{Path: path, Type: ChangeAdded, To: toValue})
+		case !inTo:
+			*changes = append(*changes, Change{Path: path, Type: ChangeRemoved, From: fromValue})
+		case !reflect.DeepEqual(fromValue, toValue):
+			*changes = append(*changes, Change{Path: path, Type: ChangeChanged, From: fromValue, To: toValue})
+		}
+	}
+}
+
+func Render(writer io.Writer, report *Report, format formatting.Format, maxColumnWidth int) error {
+	switch format {
+	case formatting.FormatJson:
+		return formatting.RenderJson(writer, report)
+	case formatting.FormatYaml:
+		return formatting.RenderYaml(writer, report)
+	case formatting.FormatTable:
+		return renderTable(writer, report, maxColumnWidth)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func renderTable(writer io.Writer, report *Report, maxColumnWidth int) error {
+	if len(report.Releases) == 0 {
+		_, err := fmt.Fprintf(writer, "🎉 All releases of %s are in sync with %s\n", style.Resource(report.Target), style.Resource(report.Source))
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"RELEASE", "VERSION", "ADDED", "CHANGED", "REMOVED", "PATHS"})
+
+	for _, release := range report.Releases {
+		version := style.Version(release.ToVersion)
+		switch release.FromVersion {
+		case "":
+			version = "(new) -> " + version
+		case release.ToVersion:
+		default:
+			version = style.Version(release.FromVersion) + " -> " + version
+		}
+
+		var paths []string
+		for _, change := range release.Changes {
+			paths = append(paths, text.Truncate(changePrefix(change.Type)+" "+change.Path, maxColumnWidth))
+		}
+
+		t.AppendRow(table.Row{
+			release.Name,
+			version,
+			release.Added,
+			release.Changed,
+			release.Removed,
+			strings.Join(paths, "\n"),
+		})
+		t.AppendSeparator()
+	}
+
+	if _, err := io.WriteString(writer, t.Render()+"\n"); err != nil {
+		return fmt.Errorf("writing drift as table: %w", err)
+	}
+
+	_, err := fmt.Fprintf(writer, "%d release(s) of %s would change upon promotion from %s, %d in sync\n",
+		len(report.Releases), style.Resource(report.Target), style.Resource(report.Source), len(report.InSync))
+	return err
+}
+
+func changePrefix(changeType ChangeType) string {
+	switch changeType {
+	case ChangeAdded:
+		return "+"
+	case ChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
@@ -0,0 +1,223 @@
+package drift
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/release/cross"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func newRelease(t *testing.T, env *v1alpha1.Environment, content string) *v1alpha1.Release {
+	file, err := yml.NewFile(env.Dir+"/releases/api.yaml", []byte(content))
+	require.NoError(t, err)
+
+	release, err := v1alpha1.LoadRelease(file)
+	require.NoError(t, err)
+	release.Environment = env
+
+	return release
+}
+
+func newCatalog(t *testing.T, stagingRelease, prodRelease string) *catalog.Catalog {
+	staging := &v1alpha1.Environment{EnvironmentMetadata: v1alpha1.EnvironmentMetadata{ObjectMeta: metav1.ObjectMeta{Name: "staging"}}, Dir: "/catalog/environments/staging"}
+	prod := &v1alpha1.Environment{EnvironmentMetadata: v1alpha1.EnvironmentMetadata{ObjectMeta: metav1.ObjectMeta{Name: "prod"}}, Dir: "/catalog/environments/prod"}
+	prod.Spec.Values = map[string]any{"domain": "acme.com"}
+
+	releases := []*v1alpha1.Release{newRelease(t, staging, stagingRelease), nil}
+	if prodRelease != "" {
+		releases[1] = newRelease(t, prod, prodRelease)
+	}
+
+	envs := []*v1alpha1.Environment{staging, prod}
+	return &catalog.Catalog{
+		Environments: envs,
+		Releases: cross.ReleaseList{
+			Environments: envs,
+			Items:        []*cross.Release{{Name: "api", Releases: releases}},
+		},
+	}
+}
+
+const stagingRelease = `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.2.0
+  values:
+    image: api:{{ .Release.Spec.Version }}
+    replicas: 1
+    host: api.staging.acme.com
+    env:
+      FEATURE: enabled
+      DEBUG: "true"
+`
+
+func TestCompute(t *testing.T) {
+	cases := []struct {
+		name             string
+		prodRelease      string
+		expectedReleases []Release
+		expectedInSync   []string
+	}{
+		{
+			name: "in sync",
+			prodRelease: `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.2.0
+  values:
+    image: api:{{ .Release.Spec.Version }}
+    replicas: 1
+    host: api.staging.acme.com
+    env:
+      FEATURE: enabled
+      DEBUG: "true"
+`,
+			expectedReleases: []Release{},
+			expectedInSync:   []string{"api"},
+		},
+		{
+			name: "locked and local values are excluded",
+			prodRelease: `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.1.0
+  values:
+    image: api:{{ .Release.Spec.Version }}
+    replicas: !lock 3
+    host: !local api.{{ .Environment.Spec.Values.domain }}
+    env:
+      FEATURE: disabled
+`,
+			expectedReleases: []Release{
+				{
+					Name:        "api",
+					FromVersion: "1.1.0",
+					ToVersion:   "1.2.0",
+					Added:       1,
+					Changed:     2,
+					Changes: []Change{
+						{Path: "env.DEBUG", Type: ChangeAdded, To: "true"},
+						{Path: "env.FEATURE", Type: ChangeChanged, From: "disabled", To: "enabled"},
+						{Path: "image", Type: ChangeChanged, From: "api:1.1.0", To: "api:1.2.0"},
+					},
+				},
+			},
+			expectedInSync: []string{},
+		},
+		{
+			name: "missing target",
+			expectedReleases: []Release{
+				{
+					Name:      "api",
+					ToVersion: "1.2.0",
+					Added:     5,
+					Changes: []Change{
+						{Path: "env.DEBUG", Type: ChangeAdded, To: "true"},
+						{Path: "env.FEATURE", Type: ChangeAdded, To: "enabled"},
+						{Path: "host", Type: ChangeAdded, To: "api.staging.acme.com"},
+						{Path: "image", Type: ChangeAdded, To: "api:1.2.0"},
+						{Path: "replicas", Type: ChangeAdded, To: 1},
+					},
+				},
+			},
+			expectedInSync: []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := Compute(context.Background(), Params{
+				Catalog: newCatalog(t, stagingRelease, tc.prodRelease),
+				Source:  "staging",
+				Target:  "prod",
+			})
+			require.NoError(t, err)
+			require.Equal(t, "staging", report.Source)
+			require.Equal(t, "prod", report.Target)
+			require.Equal(t, tc.expectedReleases, report.Releases)
+			require.Equal(t, tc.expectedInSync, report.InSync)
+		})
+	}
+}
+
+func TestComputeUnknownEnvironment(t *testing.T) {
+	_, err := Compute(context.Background(), Params{
+		Catalog: newCatalog(t, stagingRelease, ""),
+		Source:  "staging",
+		Target:  "qa",
+	})
+	require.EqualError(t, err, `getting target environment: environment "qa" not found`)
+}
+
+func TestDiffValues(t *testing.T) {
+	changes := DiffValues(
+		map[string]any{"a": 1, "list": []any{1, 2}, "nested": map[string]any{"gone": true, "same": "x"}, "type": map[string]any{"x": 1}},
+		map[string]any{"a": 1, "list": []any{1, 3}, "nested": map[string]any{"same": "x", "new": "y"}, "type": "scalar"},
+	)
+	require.Equal(t, []Change{
+		{Path: "list", Type: ChangeChanged, From: []any{1, 2}, To: []any{1, 3}},
+		{Path: "nested.gone", Type: ChangeRemoved, From: true},
+		{Path: "nested.new", Type: ChangeAdded, To: "y"},
+		{Path: "type", Type: ChangeChanged, From: map[string]any{"x": 1}, To: "scalar"},
+	}, changes)
+}
+
+func TestRenderJson(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, Render(&buffer, &Report{
+		Source: "staging",
+		Target: "prod",
+		Releases: []Release{{
+			Name:        "api",
+			FromVersion: "1.1.0",
+			ToVersion:   "1.2.0",
+			Changed:     1,
+			Changes:     []Change{{Path: "replicas", Type: ChangeChanged, From: 1, To: 2}},
+		}},
+		InSync: []string{"web"},
+	}, formatting.FormatJson, 0))
+
+	require.Equal(t, `{
+  "source": "staging",
+  "target": "prod",
+  "releases": [
+    {
+      "name": "api",
+      "fromVersion": "1.1.0",
+      "toVersion": "1.2.0",
+      "added": 0,
+      "changed": 1,
+      "removed": 0,
+      "changes": [
+        {
+          "path": "replicas",
+          "type": "changed",
+          "from": 1,
+          "to": 2
+        }
+      ]
+    }
+  ],
+  "inSync": [
+    "web"
+  ]
+}
+`, buffer.String())
+}
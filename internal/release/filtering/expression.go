@@ -0,0 +1,204 @@
+package filtering
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+// ExpressionFilter matches releases against a boolean CUE expression, such as:
+//
+//	env.name == "prod" && release.spec.chart.version =~ "^1\\." && list.Contains(project.owners, "team-a")
+//
+// The expression is evaluated against the `release`, `env` and `project` of each release. Each of them exposes its
+// `name`, `labels` and `annotations`, as well as its `spec`, whose fields are also available directly on the
+// resource for brevity (e.g. `release.version`, `release.values.image.tag` or `project.owners`). Functions of the
+// `list`, `strings`, `strconv` and `regexp` standard packages can be used without importing them. As strings compare
+// lexically, such that "10.0.0" < "2.0.0", ordering operators are rejected on fields named `version`, which can be
+// matched with regular expressions instead (e.g. `release.version =~ "^1\\."`).
+//
+// Releases that do not define the fields the expression refers to do not match. Other evaluation errors, such as
+// comparing values of different types, are recorded by Match and returned by Err.
+type ExpressionFilter struct {
+	Expression string
+	compiled   cue.Value
+	err        error
+}
+
+var expressionPackages = []string{"list", "strings", "strconv", "regexp"}
+
+func NewExpressionFilter(expr string) (*ExpressionFilter, error) {
+	parsed, err := parser.ParseExpr("expression", expr)
+	if err != nil {
+		return nil, fmt.Errorf("compiling expression %q: %w", expr, err)
+	}
+
+	// Only import packages that are used, as CUE rejects unused imports. Packages are used as the operand of top-level
+	// selectors, such that fields of the same name, as in release.values.list.enabled, are not mistaken for them.
+	used := map[string]bool{}
+	orderedVersion := false
+	ast.Walk(parsed, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := node.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		case *ast.BinaryExpr:
+			if isOrdering(node.Op) && (isVersionField(node.X) || isVersionField(node.Y)) {
+				orderedVersion = true
+			}
+			// Bounds are ordering operators too, as in release.version & <"2.0.0"
+			if node.Op == token.AND && (isVersionField(node.X) && isBound(node.Y) || isBound(node.X) && isVersionField(node.Y)) {
+				orderedVersion = true
+			}
+		}
+		return true
+	}, nil)
+
+	if orderedVersion {
+		return nil, fmt.Errorf("compiling expression %q: ordering operators compare versions as strings, such that \"10.0.0\" < \"2.0.0\", use regular expressions to match versions instead", expr)
+	}
+
+	var source strings.Builder
+	for _, pkg := range expressionPackages {
+		if used[pkg] {
+			fmt.Fprintf(&source, "import %q\n", pkg)
+		}
+	}
+
+	fmt.Fprintf(&source, "release: _\nenv: _\nproject: _\nresult: (%s)\n", expr)
+
+	compiled := cuecontext.New().CompileString(source.String())
+	if err := compiled.Err(); err != nil {
+		return nil, fmt.Errorf("compiling expression %q: %w", expr, err)
+	}
+
+	return &ExpressionFilter{Expression: expr, compiled: compiled}, nil
+}
+
+func isOrdering(op token.Token) bool {
+	return op == token.LSS || op == token.LEQ || op == token.GTR || op == token.GEQ
+}
+
+func isBound(expr ast.Expr) bool {
+	unary, ok := expr.(*ast.UnaryExpr)
+	return ok && isOrdering(unary.Op)
+}
+
+// isVersionField reports whether given expression selects a field named version, as in release.spec.chart.version or
+// release.spec["version"].
+func isVersionField(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.SelectorExpr:
+		name, _, err := ast.LabelName(expr.Sel)
+		return err == nil && name == "version"
+	case *ast.IndexExpr:
+		literal, ok := expr.Index.(*ast.BasicLit)
+		return ok && literal.Kind == token.STRING && literal.Value == `"version"`
+	case *ast.ParenExpr:
+		return isVersionField(expr.X)
+	default:
+		return false
+	}
+}
+
+func (f *ExpressionFilter) Match(rel *v1alpha1.Release) bool {
+	matched, err := f.Evaluate(rel)
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return err == nil && matched
+}
+
+// Err returns the first error Match encountered evaluating the expression, if any.
+func (f *ExpressionFilter) Err() error {
+	return f.err
+}
+
+// Evaluate evaluates the expression against given release, returning an error if it cannot be evaluated to a
+// boolean. Releases that do not define the fields the expression refers to do not match, without error.
+func (f *ExpressionFilter) Evaluate(rel *v1alpha1.Release) (bool, error) {
+	scope, err := expressionScope(rel)
+	if err != nil {
+		return false, err
+	}
+
+	value := f.compiled
+	for key, data := range scope {
+		value = value.FillPath(cue.ParsePath(key), data)
+	}
+
+	resultValue := value.LookupPath(cue.ParsePath("result"))
+	if !resultValue.IsConcrete() {
+		// References to undefined fields leave the result incomplete rather than erroneous
+		return false, nil
+	}
+
+	result, err := resultValue.Bool()
+	if err != nil {
+		return false, fmt.Errorf("evaluating expression %q against release %s: %w", f.Expression, rel.Name, err)
+	}
+	return result, nil
+}
+
+func expressionScope(rel *v1alpha1.Release) (map[string]any, error) {
+	release, err := resourceScope(rel.ObjectMeta, rel.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("release: %w", err)
+	}
+
+	env := map[string]any{}
+	if rel.Environment != nil {
+		if env, err = resourceScope(rel.Environment.ObjectMeta, rel.Environment.Spec); err != nil {
+			return nil, fmt.Errorf("environment: %w", err)
+		}
+	}
+
+	project := map[string]any{}
+	if rel.Project != nil {
+		if project, err = resourceScope(rel.Project.ObjectMeta, rel.Project.Spec); err != nil {
+			return nil, fmt.Errorf("project: %w", err)
+		}
+	}
+
+	return map[string]any{"release": release, "env": env, "project": project}, nil
+}
+
+// resourceScope returns the fields of a resource exposed to expressions, namely its metadata and spec, with fields of
+// the spec also available at the top level.
+func resourceScope(metadata metav1.ObjectMeta, spec any) (map[string]any, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling spec: %w", err)
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshalling spec: %w", err)
+	}
+
+	scope := maps.Clone(fields)
+	scope["spec"] = fields
+	scope["name"] = metadata.Name
+	scope["labels"] = nonNil(metadata.Labels)
+	scope["annotations"] = nonNil(metadata.Annotations)
+
+	return scope, nil
+}
+
+func nonNil(values map[string]string) map[string]string {
+	if values == nil {
+		return map[string]string{}
+	}
+	return values
+}
@@ -0,0 +1,133 @@
+package filtering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+func TestExpressionFilter(t *testing.T) {
+	prod := &v1alpha1.Environment{
+		EnvironmentMetadata: v1alpha1.EnvironmentMetadata{ObjectMeta: metav1.ObjectMeta{Name: "prod"}},
+		Spec:                v1alpha1.EnvironmentSpec{Order: 3},
+	}
+
+	project := &v1alpha1.Project{
+		ProjectMetadata: v1alpha1.ProjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "api"}},
+		Spec:            v1alpha1.ProjectSpec{Owners: []string{"team-a", "team-b"}},
+	}
+
+	release := &v1alpha1.Release{
+		ReleaseMetadata: v1alpha1.ReleaseMetadata{ObjectMeta: metav1.ObjectMeta{
+			Name:   "api",
+			Labels: map[string]string{"tier": "backend"},
+		}},
+		Spec: v1alpha1.ReleaseSpec{
+			Version: "1.4.0",
+			Chart:   v1alpha1.ReleaseChart{Version: "1.9.2"},
+			Values: map[string]any{
+				"replicas": 3,
+				"image":    map[string]any{"tag": "1.4.0"},
+				"list":     map[string]any{"enabled": true},
+			},
+		},
+		Environment: prod,
+		Project:     project,
+	}
+
+	cases := []struct {
+		Name       string
+		Expression string
+		Match      bool
+	}{
+		{
+			Name:       "combined conditions",
+			Expression: `env.name == "prod" && release.spec.chart.version =~ "^1\\." && list.Contains(project.owners, "team-a")`,
+			Match:      true,
+		},
+		{
+			Name:       "spec fields at top level",
+			Expression: `release.version == "1.4.0" && env.order > 2`,
+			Match:      true,
+		},
+		{
+			Name:       "values paths",
+			Expression: `release.values.replicas >= 3 && release.values.image.tag == release.version`,
+			Match:      true,
+		},
+		{
+			Name:       "fields named like packages",
+			Expression: `release.values.list.enabled && release.spec.values.list.enabled`,
+			Match:      true,
+		},
+		{
+			Name:       "labels and regular expressions",
+			Expression: `release.labels.tier =~ "^back" && strings.HasPrefix(release.name, "a")`,
+			Match:      true,
+		},
+		{
+			Name:       "no match",
+			Expression: `env.name == "staging" || !list.Contains(project.owners, "team-b")`,
+			Match:      false,
+		},
+		{
+			Name:       "missing field does not match",
+			Expression: `release.values.missing.key == "value"`,
+			Match:      false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			filter, err := NewExpressionFilter(tc.Expression)
+			require.NoError(t, err)
+			require.Equal(t, tc.Match, filter.Match(release))
+			require.NoError(t, filter.Err())
+		})
+	}
+
+	t.Run("invalid expression", func(t *testing.T) {
+		_, err := NewExpressionFilter(`release.name ==`)
+		require.ErrorContains(t, err, "compiling expression")
+	})
+
+	t.Run("non-boolean expression", func(t *testing.T) {
+		filter, err := NewExpressionFilter(`release.name`)
+		require.NoError(t, err)
+		_, err = filter.Evaluate(release)
+		require.ErrorContains(t, err, "evaluating expression")
+	})
+
+	t.Run("ordering versions", func(t *testing.T) {
+		major10 := *release
+		major10.Spec.Version = "10.0.0"
+		major10.Spec.Chart.Version = "10.1.0"
+
+		// Lexically, "10.1.0" < "2.0.0", which would wrongly match
+		for _, expr := range []string{
+			`release.spec.chart.version < "2.0.0"`,
+			`"2.0.0" > release.version`,
+			`release.spec["version"] <= "2.0.0"`,
+			`(release.version & <"2.0.0") != _|_`,
+		} {
+			_, err := NewExpressionFilter(expr)
+			require.ErrorContains(t, err, "ordering operators compare versions as strings", expr)
+		}
+
+		filter, err := NewExpressionFilter(`release.spec.chart.version =~ "^([2-9]|[1-9][0-9]+)\\." && release.version != "2.0.0"`)
+		require.NoError(t, err)
+		require.True(t, filter.Match(&major10))
+		require.False(t, filter.Match(release))
+		require.NoError(t, filter.Err())
+	})
+
+	t.Run("evaluation error", func(t *testing.T) {
+		filter, err := NewExpressionFilter(`release.name > 3`)
+		require.NoError(t, err)
+		require.False(t, filter.Match(release))
+		require.ErrorContains(t, filter.Err(), `evaluating expression "release.name > 3" against release api: result: invalid operands`)
+	})
+}
@@ -1,28 +1,150 @@
 package filtering
 
 import (
+	"fmt"
+	"path"
+	"regexp"
 	"slices"
 	"strings"
 
 	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/labels"
 )
 
 type Filter interface {
 	Match(rel *v1alpha1.Release) bool
 }
 
+// NamePatternFilter matches releases whose name matches any of a comma-separated list of patterns. Each pattern is
+// either an exact name, a glob (e.g. `payments-*`) or a regular expression delimited by slashes (e.g. `/^pay.*-api$/`).
 type NamePatternFilter struct {
-	ReleaseNames []string
+	Patterns []NamePattern
+}
+
+func NewNamePatternFilter(pattern string) (*NamePatternFilter, error) {
+	return NewNamePatternsFilter(SplitNamePatterns(pattern))
+}
+
+// SplitNamePatterns splits a comma-separated list of patterns, ignoring commas within braces, parentheses or brackets,
+// such that regular expressions like `/^worker-[a-z]{2,3}$/` are kept whole.
+func SplitNamePatterns(patterns string) []string {
+	var (
+		result []string
+		depth  int
+		start  int
+	)
+	for i := 0; i < len(patterns); i++ {
+		switch patterns[i] {
+		case '\\':
+			i++
+		case '{', '(', '[':
+			depth++
+		case '}', ')', ']':
+			depth = max(depth-1, 0)
+		case ',':
+			if depth == 0 {
+				result = append(result, patterns[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, patterns[start:])
 }
 
-func NewNamePatternFilter(pattern string) *NamePatternFilter {
-	return &NamePatternFilter{
-		ReleaseNames: strings.Split(pattern, ","),
+func NewNamePatternsFilter(patterns []string) (*NamePatternFilter, error) {
+	var filter NamePatternFilter
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		namePattern, err := ParseNamePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filter.Patterns = append(filter.Patterns, namePattern)
 	}
+	return &filter, nil
 }
 
 func (f *NamePatternFilter) Match(rel *v1alpha1.Release) bool {
-	return slices.Contains(f.ReleaseNames, rel.Name)
+	return f.MatchName(rel.Name)
+}
+
+func (f *NamePatternFilter) MatchName(name string) bool {
+	return slices.ContainsFunc(f.Patterns, func(pattern NamePattern) bool { return pattern.Match(name) })
+}
+
+// Unmatched returns the patterns that match none of given names.
+func (f *NamePatternFilter) Unmatched(names []string) []string {
+	var unmatched []string
+	for _, pattern := range f.Patterns {
+		if !slices.ContainsFunc(names, pattern.Match) {
+			unmatched = append(unmatched, pattern.String())
+		}
+	}
+	return unmatched
+}
+
+// MatchingNames returns the names matching any pattern, in the order of given names, or an error naming the patterns
+// that match none of them.
+func (f *NamePatternFilter) MatchingNames(names []string) ([]string, error) {
+	if unmatched := f.Unmatched(names); len(unmatched) > 0 {
+		return nil, fmt.Errorf("no releases matching: %s", strings.Join(unmatched, ", "))
+	}
+	var result []string
+	for _, name := range names {
+		if f.MatchName(name) {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// NamePattern matches a release name exactly, as a glob or as a regular expression.
+type NamePattern struct {
+	pattern string
+	glob    bool
+	regex   *regexp.Regexp
+}
+
+// ParseNamePattern parses a pattern that is a regular expression if delimited by slashes (e.g. `/^pay.*-api$/`), a
+// glob if it contains any of `*?[` (e.g. `payments-*`), and an exact name otherwise.
+func ParseNamePattern(pattern string) (NamePattern, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		regex, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return NamePattern{}, fmt.Errorf("invalid release name pattern %s: %w", pattern, err)
+		}
+		return NamePattern{pattern: pattern, regex: regex}, nil
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return NamePattern{}, fmt.Errorf("invalid release name pattern %s: %w", pattern, err)
+		}
+		return NamePattern{pattern: pattern, glob: true}, nil
+	}
+	return NamePattern{pattern: pattern}, nil
+}
+
+// IsExact reports whether the pattern is an exact name, as opposed to a glob or regular expression.
+func (p NamePattern) IsExact() bool {
+	return p.regex == nil && !p.glob
+}
+
+func (p NamePattern) Match(name string) bool {
+	switch {
+	case p.regex != nil:
+		return p.regex.MatchString(name)
+	case p.glob:
+		matched, _ := path.Match(p.pattern, name)
+		return matched
+	default:
+		return p.pattern == name
+	}
+}
+
+func (p NamePattern) String() string {
+	return p.pattern
 }
 
 type SpecificReleasesFilter struct {
@@ -62,3 +184,46 @@ func (f *OwnerFilter) Match(rel *v1alpha1.Release) bool {
 	}
 	return false
 }
+
+// ReleaseGroupFilter matches the releases that are members of a release group, either by name or by label.
+type ReleaseGroupFilter struct {
+	ReleaseNames []string
+	Selectors    []labels.Selector
+}
+
+func NewReleaseGroupFilter(group *v1alpha1.ReleaseGroup) (*ReleaseGroupFilter, error) {
+	selectors, err := labels.ParseSelectors(group.Spec.Selectors)
+	if err != nil {
+		return nil, fmt.Errorf("parsing selectors of release group %s: %w", group.Name, err)
+	}
+	return &ReleaseGroupFilter{
+		ReleaseNames: group.Spec.Releases,
+		Selectors:    selectors,
+	}, nil
+}
+
+func (f *ReleaseGroupFilter) Match(rel *v1alpha1.Release) bool {
+	if slices.Contains(f.ReleaseNames, rel.Name) {
+		return true
+	}
+	_, ok := labels.FirstMatch(f.Selectors, rel.Labels)
+	return ok
+}
+
+// LabelSelectorFilter matches the releases whose labels match all selectors of a label selector expression, such as
+// `team=fe,tier in (api,worker),!preview`.
+type LabelSelectorFilter struct {
+	Selectors []labels.Selector
+}
+
+func NewLabelSelectorFilter(expr string) (*LabelSelectorFilter, error) {
+	selectors, err := labels.ParseExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &LabelSelectorFilter{Selectors: selectors}, nil
+}
+
+func (f *LabelSelectorFilter) Match(rel *v1alpha1.Release) bool {
+	return labels.MatchAll(f.Selectors, rel.Labels)
+}
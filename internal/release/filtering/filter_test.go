@@ -0,0 +1,77 @@
+package filtering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamePatternFilter(t *testing.T) {
+	names := []string{"api", "payments-api", "payments-worker", "worker-ca", "worker-eu", "worker-us"}
+
+	cases := []struct {
+		Name     string
+		Pattern  string
+		Expected []string
+		Err      string
+	}{
+		{
+			Name:     "exact names",
+			Pattern:  "api,worker-ca",
+			Expected: []string{"api", "worker-ca"},
+		},
+		{
+			Name:     "glob",
+			Pattern:  "payments-*",
+			Expected: []string{"payments-api", "payments-worker"},
+		},
+		{
+			Name:     "regex",
+			Pattern:  "/^worker-(ca|eu)$/",
+			Expected: []string{"worker-ca", "worker-eu"},
+		},
+		{
+			Name:     "mixed patterns",
+			Pattern:  "*-api, /-us$/",
+			Expected: []string{"payments-api", "worker-us"},
+		},
+		{
+			Name:     "regex with commas",
+			Pattern:  "api,/^worker-[a-z]{2,3}$/,/^payments-(api|worker)$/",
+			Expected: []string{"api", "payments-api", "payments-worker", "worker-ca", "worker-eu", "worker-us"},
+		},
+		{
+			Name:    "unmatched patterns",
+			Pattern: "api,billing-*,/^cron/",
+			Err:     "no releases matching: billing-*, /^cron/",
+		},
+		{
+			Name:    "invalid regex",
+			Pattern: "/(/",
+			Err:     "invalid release name pattern /(/: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			Name:    "invalid glob",
+			Pattern: "[a",
+			Err:     "invalid release name pattern [a: syntax error in pattern",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			filter, err := NewNamePatternFilter(tc.Pattern)
+			if err == nil {
+				var matching []string
+				matching, err = filter.MatchingNames(names)
+				if err == nil {
+					require.Equal(t, tc.Expected, matching)
+				}
+			}
+			if tc.Err != "" {
+				require.EqualError(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
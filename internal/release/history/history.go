@@ -0,0 +1,186 @@
+// Package history reconstructs the versions a release went through in each environment from the catalog git history.
+package history
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/internal/text"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+// Entry describes a change of version or chart version of a release in a given environment.
+type Entry struct {
+	Environment  string    `json:"environment" yaml:"environment"`
+	Version      string    `json:"version" yaml:"version"`
+	ChartVersion string    `json:"chartVersion,omitempty" yaml:"chartVersion,omitempty"`
+	Commit       string    `json:"commit" yaml:"commit"`
+	Author       string    `json:"author" yaml:"author"`
+	Time         time.Time `json:"time" yaml:"time"`
+	PullRequest  int       `json:"pullRequest,omitempty" yaml:"pullRequest,omitempty"`
+	Message      string    `json:"message" yaml:"message"`
+}
+
+type Params struct {
+	Catalog  *catalog.Catalog
+	Provider Provider
+
+	// Release is the name of the release to get the history of.
+	Release string
+
+	// Environments are the names of environments to get the history from (all if empty).
+	Environments []string
+}
+
+// Get returns the history of the given release in each environment, ordered by environment and then from most recent
+// to oldest change.
+func Get(params Params) ([]Entry, error) {
+	for _, name := range params.Environments {
+		if !slices.Contains(params.Catalog.GetEnvironmentNames(), name) {
+			return nil, fmt.Errorf("unknown environment: %s", name)
+		}
+	}
+
+	var releases []*v1alpha1.Release
+	for _, crossRelease := range params.Catalog.Releases.Items {
+		if crossRelease.Name != params.Release {
+			continue
+		}
+		for _, release := range crossRelease.Releases {
+			if release == nil {
+				continue
+			}
+			if len(params.Environments) > 0 && !slices.Contains(params.Environments, release.Environment.Name) {
+				continue
+			}
+			releases = append(releases, release)
+		}
+	}
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("release %s not found in selected environments", params.Release)
+	}
+
+	var entries []Entry
+	for _, release := range releases {
+		releaseEntries, err := getReleaseHistory(params.Provider, release)
+		if err != nil {
+			return nil, fmt.Errorf("getting history of release %s in environment %s: %w", release.Name, release.Environment.Name, err)
+		}
+		entries = append(entries, releaseEntries...)
+	}
+
+	return entries, nil
+}
+
+// getReleaseHistory returns the changes of version and chart version of given release, from most recent to oldest.
+func getReleaseHistory(provider Provider, release *v1alpha1.Release) ([]Entry, error) {
+	revisions, err := provider.GetFileRevisions(release.File.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, revision := range slices.Backward(revisions) {
+		file, err := yml.NewFile(release.File.Path, revision.Content)
+		if err != nil {
+			return nil, fmt.Errorf("parsing revision %s: %w", revision.Commit, err)
+		}
+
+		entry := Entry{
+			Environment:  release.Environment.Name,
+			Version:      yml.FindNodeValueOrDefault(file.Tree, "spec.version", ""),
+			ChartVersion: yml.FindNodeValueOrDefault(file.Tree, "spec.chart.version", ""),
+			Commit:       revision.Commit,
+			Author:       revision.Author,
+			Time:         revision.Time,
+			PullRequest:  ParsePullRequestNumber(revision.Message),
+			Message:      subject(revision.Message),
+		}
+
+		if len(entries) > 0 {
+			last := entries[len(entries)-1]
+			if last.Version == entry.Version && last.ChartVersion == entry.ChartVersion {
+				continue
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	slices.Reverse(entries)
+	return entries, nil
+}
+
+// pullRequestNumberRegex matches the pull request number GitHub adds to the subject of commits when squash merging
+// (e.g. "Promote foo 1.2.3 -> 1.2.4 (staging -> prod) (#123)") or to the subject of merge commits.
+var pullRequestNumberRegex = regexp.MustCompile(`\(#(\d+)\)$|^Merge pull request #(\d+)\b`)
+
+// ParsePullRequestNumber returns the number of the pull request referenced in the subject of given commit message,
+// or 0 if none.
+func ParsePullRequestNumber(message string) int {
+	matches := pullRequestNumberRegex.FindStringSubmatch(subject(message))
+	if matches == nil {
+		return 0
+	}
+	number, _ := strconv.Atoi(matches[1] + matches[2])
+	return number
+}
+
+func subject(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(line)
+}
+
+func Render(writer io.Writer, entries []Entry, format formatting.Format, maxColumnWidth int) error {
+	switch format {
+	case formatting.FormatJson:
+		return formatting.RenderJson(writer, entries)
+	case formatting.FormatYaml:
+		return formatting.RenderYaml(writer, entries)
+	case formatting.FormatTable:
+		return renderTable(writer, entries, maxColumnWidth)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func renderTable(writer io.Writer, entries []Entry, maxColumnWidth int) error {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"ENVIRONMENT", "VERSION", "CHART", "DATE", "AUTHOR", "PR", "COMMIT", "MESSAGE"})
+
+	for _, entry := range entries {
+		pullRequest := ""
+		if entry.PullRequest != 0 {
+			pullRequest = "#" + strconv.Itoa(entry.PullRequest)
+		}
+
+		t.AppendRow(table.Row{
+			entry.Environment,
+			style.Version(entry.Version),
+			entry.ChartVersion,
+			entry.Time.Local().Format(time.DateTime),
+			entry.Author,
+			pullRequest,
+			entry.Commit[:min(7, len(entry.Commit))],
+			text.Truncate(entry.Message, maxColumnWidth),
+		})
+	}
+
+	if _, err := io.WriteString(writer, t.Render()+"\n"); err != nil {
+		return fmt.Errorf("writing release history as table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package history
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/formatting"
+	"github.com/nestoca/joy/internal/release/cross"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func newRevision(commit, message, version, chartVersion string, day int) Revision {
+	return Revision{
+		Commit:  commit,
+		Author:  "john",
+		Time:    time.Date(2024, time.March, day, 12, 0, 0, 0, time.UTC),
+		Message: message,
+		Content: []byte(`apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: ` + version + `
+  chart:
+    version: ` + chartVersion + `
+`),
+	}
+}
+
+func newCatalog(t *testing.T, envNames ...string) *catalog.Catalog {
+	var (
+		envs     []*v1alpha1.Environment
+		releases []*v1alpha1.Release
+	)
+	for _, name := range envNames {
+		env := &v1alpha1.Environment{EnvironmentMetadata: v1alpha1.EnvironmentMetadata{ObjectMeta: metav1.ObjectMeta{Name: name}}}
+		envs = append(envs, env)
+
+		file, err := yml.NewFile("/catalog/environments/"+name+"/releases/api.yaml", []byte("apiVersion: joy.nesto.ca/v1alpha1\nkind: Release\nmetadata:\n  name: api\n"))
+		require.NoError(t, err)
+		release, err := v1alpha1.LoadRelease(file)
+		require.NoError(t, err)
+		release.Environment = env
+		releases = append(releases, release)
+	}
+
+	return &catalog.Catalog{
+		Environments: envs,
+		Releases: cross.ReleaseList{
+			Environments: envs,
+			Items:        []*cross.Release{{Name: "api", Releases: releases}},
+		},
+	}
+}
+
+func TestGet(t *testing.T) {
+	provider := &ProviderMock{
+		GetFileRevisionsFunc: func(file string) ([]Revision, error) {
+			switch file {
+			case "/catalog/environments/staging/releases/api.yaml":
+				return []Revision{
+					newRevision("c4", "Promote api 1.1.0 -> 1.2.0 (dev -> staging) (#42)", "1.2.0", "2.0.0", 4),
+					newRevision("c3", "Update values of api", "1.1.0", "2.0.0", 3),
+					newRevision("c2", "Merge pull request #12 from acme/promote-api\n\nPromote api", "1.1.0", "2.0.0", 2),
+					newRevision("c1", "Add api", "1.0.0", "1.0.0", 1),
+				}, nil
+			case "/catalog/environments/prod/releases/api.yaml":
+				return []Revision{
+					newRevision("c5", "Promote api 1.1.0 -> 1.2.0 (staging -> prod) (#43)", "1.2.0", "2.0.0", 5),
+				}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	entries, err := Get(Params{
+		Catalog:  newCatalog(t, "staging", "prod"),
+		Provider: provider,
+		Release:  "api",
+	})
+	require.NoError(t, err)
+
+	type summary struct {
+		Environment  string
+		Version      string
+		ChartVersion string
+		Commit       string
+		PullRequest  int
+	}
+	var actual []summary
+	for _, entry := range entries {
+		actual = append(actual, summary{entry.Environment, entry.Version, entry.ChartVersion, entry.Commit, entry.PullRequest})
+	}
+
+	require.Equal(t, []summary{
+		{"staging", "1.2.0", "2.0.0", "c4", 42},
+		{"staging", "1.1.0", "2.0.0", "c2", 12},
+		{"staging", "1.0.0", "1.0.0", "c1", 0},
+		{"prod", "1.2.0", "2.0.0", "c5", 43},
+	}, actual)
+
+	entries, err = Get(Params{
+		Catalog:      newCatalog(t, "staging", "prod"),
+		Provider:     provider,
+		Release:      "api",
+		Environments: []string{"prod"},
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	_, err = Get(Params{
+		Catalog:      newCatalog(t, "staging", "prod"),
+		Provider:     provider,
+		Release:      "api",
+		Environments: []string{"qa"},
+	})
+	require.EqualError(t, err, "unknown environment: qa")
+}
+
+func TestParsePullRequestNumber(t *testing.T) {
+	cases := []struct {
+		message  string
+		expected int
+	}{
+		{message: "Promote api 1.1.0 -> 1.2.0 (staging -> prod) (#123)", expected: 123},
+		{message: "Promote 2 releases (staging -> prod) (#7)\n\nBody mentioning (#99)", expected: 7},
+		{message: "Merge pull request #456 from acme/branch", expected: 456},
+		{message: "Promote api 1.1.0 -> 1.2.0 (staging -> prod)", expected: 0},
+		{message: "Fix #12 in values", expected: 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.message, func(t *testing.T) {
+			require.Equal(t, tc.expected, ParsePullRequestNumber(tc.message))
+		})
+	}
+}
+
+func TestRenderJson(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, Render(&buffer, []Entry{{
+		Environment: "prod",
+		Version:     "1.2.0",
+		Commit:      "c5",
+		Author:      "john",
+		Time:        time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC),
+		PullRequest: 43,
+		Message:     "Promote api",
+	}}, formatting.FormatJson, 0))
+
+	require.Equal(t, `[
+  {
+    "environment": "prod",
+    "version": "1.2.0",
+    "commit": "c5",
+    "author": "john",
+    "time": "2024-03-05T12:00:00Z",
+    "pullRequest": 43,
+    "message": "Promote api"
+  }
+]
+`, buffer.String())
+}
@@ -0,0 +1,20 @@
+package history
+
+import (
+	"time"
+)
+
+// Revision is the content of a file as of a given commit.
+type Revision struct {
+	Commit  string
+	Author  string
+	Time    time.Time
+	Message string
+	Content []byte
+}
+
+//go:generate moq -stub -out ./provider_mock.go . Provider
+type Provider interface {
+	// GetFileRevisions returns the revisions of given file, from most recent to oldest.
+	GetFileRevisions(file string) ([]Revision, error)
+}
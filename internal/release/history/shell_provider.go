@@ -0,0 +1,44 @@
+package history
+
+import (
+	"fmt"
+
+	"github.com/nestoca/joy/internal/git"
+)
+
+type ShellProvider struct {
+	dir string
+}
+
+func NewShellProvider(dir string) *ShellProvider {
+	return &ShellProvider{dir: dir}
+}
+
+func (p *ShellProvider) GetFileRevisions(file string) ([]Revision, error) {
+	entries, err := git.GetFileLog(p.dir, file)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]string, len(entries))
+	for i, entry := range entries {
+		commits[i] = entry.Commit
+	}
+
+	contents, err := git.ShowFileRevisions(p.dir, file, commits)
+	if err != nil {
+		return nil, fmt.Errorf("getting revisions: %w", err)
+	}
+
+	revisions := make([]Revision, len(entries))
+	for i, entry := range entries {
+		revisions[i] = Revision{
+			Commit:  entry.Commit,
+			Author:  entry.Author,
+			Time:    entry.Time,
+			Message: entry.Message,
+			Content: contents[i],
+		}
+	}
+	return revisions, nil
+}
@@ -0,0 +1,50 @@
+package history
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellProvider(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "releases", "api.yaml")
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=john", "-c", "user.email=john@acme.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	commitFile := func(content, message string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+		git("add", "-A")
+		git("commit", "-m", message)
+	}
+
+	git("init", "-q")
+	commitFile("version: 1.0.0\n", "Add api")
+	commitFile("version: 1.1.0\n", "Upgrade api")
+	git("rm", "-q", file)
+	git("commit", "-m", "Remove api")
+	commitFile("version: 2.0.0\n", "Restore api")
+
+	revisions, err := NewShellProvider(dir).GetFileRevisions(file)
+	require.NoError(t, err)
+
+	var messages, contents []string
+	for _, revision := range revisions {
+		messages = append(messages, revision.Message)
+		contents = append(contents, string(revision.Content))
+		require.Equal(t, "john", revision.Author)
+	}
+
+	require.Equal(t, []string{"Restore api", "Upgrade api", "Add api"}, messages)
+	require.Equal(t, []string{"version: 2.0.0\n", "version: 1.1.0\n", "version: 1.0.0\n"}, contents)
+}
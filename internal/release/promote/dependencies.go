@@ -0,0 +1,50 @@
+package promote
+
+import (
+	"slices"
+
+	"github.com/nestoca/joy/internal/release/cross"
+	"github.com/nestoca/joy/internal/style"
+)
+
+// withDependencies returns the selected releases along with the releases they transitively depend on that are out
+// of sync in the target environment, when include is true. Otherwise, and for omitted dependencies, it only warns
+// about such dependencies not being promoted.
+func (p *Promotion) withDependencies(list, selected cross.ReleaseList, include bool, omit []string) cross.ReleaseList {
+	targetEnv := list.Environments[1]
+	result := cross.ReleaseList{Environments: selected.Environments, Items: slices.Clone(selected.Items)}
+
+	isSelected := func(name string) bool {
+		return slices.ContainsFunc(result.Items, func(item *cross.Release) bool { return item.Name == name })
+	}
+
+	for i := 0; i < len(result.Items); i++ {
+		item := result.Items[i]
+		source := item.Releases[0]
+		if source == nil {
+			continue
+		}
+
+		for _, dependency := range source.Dependencies {
+			if isSelected(dependency.Name) {
+				continue
+			}
+
+			index := slices.IndexFunc(list.Items, func(candidate *cross.Release) bool { return candidate.Name == dependency.Name })
+			if index == -1 || list.Items[index].PromotedFile == nil {
+				// Dependency already in sync in target environment
+				continue
+			}
+
+			if !include || slices.Contains(omit, dependency.Name) {
+				p.printf("⚠️ Release %s depends on %s, which is out of sync in %s but not selected for promotion (use --with-dependencies to include it)\n", style.Resource(item.Name), style.Resource(dependency.Name), style.Resource(targetEnv.Name))
+				continue
+			}
+
+			p.printf("🔗 Including release %s required by %s\n", style.Resource(dependency.Name), style.Resource(item.Name))
+			result.Items = append(result.Items, list.Items[index])
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,211 @@
+package promote
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/release/cross"
+)
+
+// Names of the promotion gates, as specified in environments and via the --override-gate flag.
+const (
+	GateMinHoursInSource      = "minHoursInSource"
+	GateNoPrerelease          = "noPrerelease"
+	GateTimeWindows           = "timeWindows"
+	GateBlockedDays           = "blockedDays"
+	GateRequireOwnerReviewers = "requireOwnerReviewers"
+)
+
+var gateNames = []string{
+	GateMinHoursInSource,
+	GateNoPrerelease,
+	GateTimeWindows,
+	GateBlockedDays,
+	GateRequireOwnerReviewers,
+}
+
+// GateViolation describes a promotion gate of a target environment that blocks the promotion of a release, or of
+// all releases when Release is empty.
+type GateViolation struct {
+	Gate        string
+	Environment string
+	Release     string
+	Reason      string
+}
+
+func (v GateViolation) subject() string {
+	if v.Release == "" {
+		return "all releases"
+	}
+	return "release " + v.Release
+}
+
+// ValidateGateNames returns an error if any of given names is not a known promotion gate.
+func ValidateGateNames(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(gateNames, name) {
+			return fmt.Errorf("unknown promotion gate %q (expecting one of: %s)", name, strings.Join(gateNames, ", "))
+		}
+	}
+	return nil
+}
+
+// evaluateGates evaluates the promotion gates of the target environment of each hop against the releases to promote,
+// returning the violations found as well as the project owners to add as reviewers.
+func (p *Promotion) evaluateGates(hops []cross.ReleaseList, all cross.ReleaseList) ([]GateViolation, []string, error) {
+	now := time.Now()
+	if p.Now != nil {
+		now = p.Now()
+	}
+
+	var (
+		violations []GateViolation
+		owners     []string
+	)
+
+	for i, hop := range hops {
+		sourceEnv, targetEnv := hop.Environments[0], hop.Environments[1]
+		gates := targetEnv.Spec.Promotion.Gates
+
+		if !hop.HasAnyPromotableReleases() {
+			continue
+		}
+
+		timeViolations, err := evaluateTimeGates(gates, targetEnv.Name, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("evaluating time gates of environment %s: %w", targetEnv.Name, err)
+		}
+		violations = append(violations, timeViolations...)
+
+		for _, crossRelease := range hop.SortedCrossReleases() {
+			if crossRelease.PromotedFile == nil {
+				continue
+			}
+
+			sourceRelease := crossRelease.Releases[0]
+			violation := func(gate, format string, args ...any) GateViolation {
+				return GateViolation{
+					Gate:        gate,
+					Environment: targetEnv.Name,
+					Release:     crossRelease.Name,
+					Reason:      fmt.Sprintf(format, args...),
+				}
+			}
+
+			if gates.NoPrerelease && IsPrerelease(sourceRelease) {
+				violations = append(violations, violation(GateNoPrerelease, "version %s is a prerelease", sourceRelease.Spec.Version))
+			}
+
+			if gates.MinHoursInSource > 0 && !crossRelease.VersionInSync {
+				hours, err := p.getHoursInSource(sourceEnv, sourceRelease, all, i > 0, now)
+				if err != nil {
+					return nil, nil, fmt.Errorf("getting time spent by release %s in %s: %w", crossRelease.Name, sourceEnv.Name, err)
+				}
+				if hours < gates.MinHoursInSource {
+					violations = append(violations, violation(GateMinHoursInSource, "version %s has been in %s for %dh, at least %dh required",
+						sourceRelease.Spec.Version, sourceEnv.Name, hours, gates.MinHoursInSource))
+				}
+			}
+
+			if gates.RequireOwnerReviewers {
+				if sourceRelease.Project == nil || len(sourceRelease.Project.Spec.Owners) == 0 {
+					violations = append(violations, violation(GateRequireOwnerReviewers, "project defines no owners to review promotion"))
+				} else {
+					owners = MergeUnique(owners, sourceRelease.Project.Spec.Owners)
+				}
+			}
+		}
+	}
+
+	return violations, owners, nil
+}
+
+// getHoursInSource returns the number of whole hours the version of given source release has been in its environment.
+// For intermediate hops, the version is only considered present if it is already in the catalog.
+func (p *Promotion) getHoursInSource(sourceEnv *v1alpha1.Environment, sourceRelease *v1alpha1.Release, all cross.ReleaseList, intermediate bool, now time.Time) (int, error) {
+	if intermediate {
+		existing, err := all.GetEnvironmentRelease(sourceEnv, sourceRelease.Name)
+		if err != nil || existing == nil || existing.Spec.Version != sourceRelease.Spec.Version {
+			return 0, nil
+		}
+		sourceRelease = existing
+	}
+
+	introduced, err := p.GitProvider.GetVersionIntroductionTime(sourceRelease.File.Path, sourceRelease.Spec.Version)
+	if err != nil {
+		return 0, err
+	}
+	if introduced.IsZero() {
+		return 0, nil
+	}
+
+	return int(now.Sub(introduced).Hours()), nil
+}
+
+// evaluateTimeGates evaluates the gates restricting when promotions to given environment are allowed.
+func evaluateTimeGates(gates v1alpha1.PromotionGates, envName string, now time.Time) ([]GateViolation, error) {
+	if gates.TimeZone != "" {
+		location, err := time.LoadLocation(gates.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("loading time zone: %w", err)
+		}
+		now = now.In(location)
+	} else {
+		now = now.UTC()
+	}
+
+	weekday := now.Weekday().String()
+	previousWeekday := now.AddDate(0, 0, -1).Weekday().String()
+	timeOfDay := now.Format("15:04")
+
+	var violations []GateViolation
+
+	if containsDay(gates.BlockedDays, weekday) {
+		violations = append(violations, GateViolation{
+			Gate:        GateBlockedDays,
+			Environment: envName,
+			Reason:      fmt.Sprintf("promotions are not allowed on %s", weekday),
+		})
+	}
+
+	if len(gates.TimeWindows) > 0 {
+		allowed := slices.ContainsFunc(gates.TimeWindows, func(window v1alpha1.PromotionTimeWindow) bool {
+			appliesTo := func(day string) bool { return len(window.Days) == 0 || containsDay(window.Days, day) }
+			if window.Start <= window.End {
+				return appliesTo(weekday) && timeOfDay >= window.Start && timeOfDay < window.End
+			}
+			// Windows ending before they start cross midnight, their end belonging to the day after they start
+			return (appliesTo(weekday) && timeOfDay >= window.Start) || (appliesTo(previousWeekday) && timeOfDay < window.End)
+		})
+		if !allowed {
+			violations = append(violations, GateViolation{
+				Gate:        GateTimeWindows,
+				Environment: envName,
+				Reason:      fmt.Sprintf("%s %s is outside of allowed time windows", weekday, timeOfDay),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+func containsDay(days []string, day string) bool {
+	return slices.ContainsFunc(days, func(value string) bool {
+		return strings.EqualFold(value, day)
+	})
+}
+
+// renderOverriddenGates renders the given gate overrides as a markdown section to record in pull request body.
+func renderOverriddenGates(violations []GateViolation) string {
+	var builder strings.Builder
+	builder.WriteString("\n\n# Overridden promotion gates\n\n")
+	builder.WriteString("| Gate | Environment | Subject | Reason |\n")
+	builder.WriteString("|:---|:---|:---|:---|\n")
+	for _, violation := range violations {
+		fmt.Fprintf(&builder, "| %s | %s | %s | %s |\n", violation.Gate, violation.Environment, violation.subject(), violation.Reason)
+	}
+	return builder.String()
+}
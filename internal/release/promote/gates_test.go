@@ -0,0 +1,109 @@
+package promote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+func TestEvaluateTimeGates(t *testing.T) {
+	// Friday, 2024-03-15 14:30 UTC
+	friday := time.Date(2024, time.March, 15, 14, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name          string
+		gates         v1alpha1.PromotionGates
+		now           time.Time
+		expectedGates []string
+	}{
+		{
+			name:          "no gates",
+			now:           friday,
+			expectedGates: nil,
+		},
+		{
+			name:          "blocked day",
+			gates:         v1alpha1.PromotionGates{BlockedDays: []string{"friday"}},
+			now:           friday,
+			expectedGates: []string{GateBlockedDays},
+		},
+		{
+			name:          "other blocked day",
+			gates:         v1alpha1.PromotionGates{BlockedDays: []string{"Saturday", "Sunday"}},
+			now:           friday,
+			expectedGates: nil,
+		},
+		{
+			name: "within time window",
+			gates: v1alpha1.PromotionGates{TimeWindows: []v1alpha1.PromotionTimeWindow{
+				{Days: []string{"Monday"}, Start: "09:00", End: "17:00"},
+				{Days: []string{"Friday"}, Start: "09:00", End: "15:00"},
+			}},
+			now:           friday,
+			expectedGates: nil,
+		},
+		{
+			name: "outside of time window",
+			gates: v1alpha1.PromotionGates{TimeWindows: []v1alpha1.PromotionTimeWindow{
+				{Start: "09:00", End: "12:00"},
+			}},
+			now:           friday,
+			expectedGates: []string{GateTimeWindows},
+		},
+		{
+			name: "within time window crossing midnight",
+			gates: v1alpha1.PromotionGates{TimeWindows: []v1alpha1.PromotionTimeWindow{
+				{Days: []string{"Thursday"}, Start: "22:00", End: "02:00"},
+			}},
+			now:           time.Date(2024, time.March, 15, 1, 30, 0, 0, time.UTC),
+			expectedGates: nil,
+		},
+		{
+			name: "outside of time window crossing midnight",
+			gates: v1alpha1.PromotionGates{TimeWindows: []v1alpha1.PromotionTimeWindow{
+				{Days: []string{"Friday"}, Start: "22:00", End: "02:00"},
+			}},
+			now:           time.Date(2024, time.March, 15, 1, 30, 0, 0, time.UTC),
+			expectedGates: []string{GateTimeWindows},
+		},
+		{
+			name: "start of time window crossing midnight",
+			gates: v1alpha1.PromotionGates{TimeWindows: []v1alpha1.PromotionTimeWindow{
+				{Days: []string{"Friday"}, Start: "14:00", End: "02:00"},
+			}},
+			now:           friday,
+			expectedGates: nil,
+		},
+		{
+			name: "time window in time zone",
+			gates: v1alpha1.PromotionGates{
+				TimeZone:    "America/Toronto",
+				TimeWindows: []v1alpha1.PromotionTimeWindow{{Start: "09:00", End: "12:00"}},
+			},
+			now:           friday,
+			expectedGates: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			violations, err := evaluateTimeGates(tc.gates, "prod", tc.now)
+			require.NoError(t, err)
+
+			var gates []string
+			for _, violation := range violations {
+				require.Equal(t, "prod", violation.Environment)
+				gates = append(gates, violation.Gate)
+			}
+			require.Equal(t, tc.expectedGates, gates)
+		})
+	}
+}
+
+func TestValidateGateNames(t *testing.T) {
+	require.NoError(t, ValidateGateNames([]string{GateNoPrerelease, GateBlockedDays}))
+	require.EqualError(t, ValidateGateNames([]string{"weekend"}), `unknown promotion gate "weekend" (expecting one of: minHoursInSource, noPrerelease, timeWindows, blockedDays, requireOwnerReviewers)`)
+}
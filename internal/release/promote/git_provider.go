@@ -1,7 +1,13 @@
 package promote
 
+import "time"
+
 //go:generate moq -stub -out ./git_provider_mock.go . GitProvider
 type GitProvider interface {
 	CreateAndPushBranchWithFiles(branchName string, files []string, message string) error
 	CheckoutMasterBranch() error
+
+	// GetVersionIntroductionTime returns the time at which given version was introduced in given release file,
+	// or zero time if it cannot be found in the history.
+	GetVersionIntroductionTime(file string, version string) (time.Time, error)
 }
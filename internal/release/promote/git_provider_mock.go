@@ -5,6 +5,7 @@ package promote
 
 import (
 	"sync"
+	"time"
 )
 
 // Ensure, that GitProviderMock does implement GitProvider.
@@ -23,6 +24,9 @@ var _ GitProvider = &GitProviderMock{}
 //			CreateAndPushBranchWithFilesFunc: func(branchName string, files []string, message string) error {
 //				panic("mock out the CreateAndPushBranchWithFiles method")
 //			},
+//			GetVersionIntroductionTimeFunc: func(file string, version string) (time.Time, error) {
+//				panic("mock out the GetVersionIntroductionTime method")
+//			},
 //		}
 //
 //		// use mockedGitProvider in code that requires GitProvider
@@ -36,6 +40,9 @@ type GitProviderMock struct {
 	// CreateAndPushBranchWithFilesFunc mocks the CreateAndPushBranchWithFiles method.
 	CreateAndPushBranchWithFilesFunc func(branchName string, files []string, message string) error
 
+	// GetVersionIntroductionTimeFunc mocks the GetVersionIntroductionTime method.
+	GetVersionIntroductionTimeFunc func(file string, version string) (time.Time, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// CheckoutMasterBranch holds details about calls to the CheckoutMasterBranch method.
@@ -50,9 +57,17 @@ type GitProviderMock struct {
 			// Message is the message argument value.
 			Message string
 		}
+		// GetVersionIntroductionTime holds details about calls to the GetVersionIntroductionTime method.
+		GetVersionIntroductionTime []struct {
+			// File is the file argument value.
+			File string
+			// Version is the version argument value.
+			Version string
+		}
 	}
 	lockCheckoutMasterBranch         sync.RWMutex
 	lockCreateAndPushBranchWithFiles sync.RWMutex
+	lockGetVersionIntroductionTime   sync.RWMutex
 }
 
 // CheckoutMasterBranch calls CheckoutMasterBranchFunc.
@@ -127,3 +142,43 @@ func (mock *GitProviderMock) CreateAndPushBranchWithFilesCalls() []struct {
 	mock.lockCreateAndPushBranchWithFiles.RUnlock()
 	return calls
 }
+
+// GetVersionIntroductionTime calls GetVersionIntroductionTimeFunc.
+func (mock *GitProviderMock) GetVersionIntroductionTime(file string, version string) (time.Time, error) {
+	callInfo := struct {
+		File    string
+		Version string
+	}{
+		File:    file,
+		Version: version,
+	}
+	mock.lockGetVersionIntroductionTime.Lock()
+	mock.calls.GetVersionIntroductionTime = append(mock.calls.GetVersionIntroductionTime, callInfo)
+	mock.lockGetVersionIntroductionTime.Unlock()
+	if mock.GetVersionIntroductionTimeFunc == nil {
+		var (
+			timeOut time.Time
+			errOut  error
+		)
+		return timeOut, errOut
+	}
+	return mock.GetVersionIntroductionTimeFunc(file, version)
+}
+
+// GetVersionIntroductionTimeCalls gets all the calls that were made to GetVersionIntroductionTime.
+// Check the length with:
+//
+//	len(mockedGitProvider.GetVersionIntroductionTimeCalls())
+func (mock *GitProviderMock) GetVersionIntroductionTimeCalls() []struct {
+	File    string
+	Version string
+} {
+	var calls []struct {
+		File    string
+		Version string
+	}
+	mock.lockGetVersionIntroductionTime.RLock()
+	calls = mock.calls.GetVersionIntroductionTime
+	mock.lockGetVersionIntroductionTime.RUnlock()
+	return calls
+}
@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"slices"
 	"strings"
@@ -18,11 +19,27 @@ import (
 )
 
 const (
-	defaultCommitAndPRTemplate = `Promote {{ len .Releases }} releases ({{ .SourceEnvironment.Name }} -> {{ .TargetEnvironment.Name }})`
+	defaultCommitAndPRTemplate = `Promote {{ len .Releases }} releases ({{ .SourceEnvironment.Name }} -> {{ .TargetEnvironment.Name }})
+{{- if .Hops }}
+
+Promotion path:
+{{- range .Hops }}
+- {{ .SourceEnvironment.Name }} -> {{ .TargetEnvironment.Name }}: {{ range $i, $release := .Releases }}{{ if $i }}, {{ end }}{{ $release.Name }}{{ end }}
+{{- end }}
+{{- end }}
+{{- $dependencies := false }}{{ range .Releases }}{{ if .DependsOn }}{{ $dependencies = true }}{{ end }}{{ end }}
+{{- if $dependencies }}
+
+Dependencies (releases are promoted after the releases they depend on):
+{{- range .Releases }}{{ if .DependsOn }}
+- {{ .Name }} depends on {{ join ", " .DependsOn }}
+{{- end }}{{ end }}
+{{- end }}`
 )
 
 type PerformOpts struct {
-	list                cross.ReleaseList
+	hops                []cross.ReleaseList
+	stacked             bool
 	autoMerge           bool
 	draft               bool
 	dryRun              bool
@@ -33,16 +50,103 @@ type PerformOpts struct {
 	infoProvider        info.Provider
 	linksProvider       links.Provider
 	reviewers           []string
+	overriddenGates     []GateViolation
+	out                 io.Writer
 }
 
-// perform performs the promotion of all releases in given list and returns PR url if any
-func (p *Promotion) perform(opts PerformOpts) (string, error) {
-	if len(opts.list.Environments) != 2 {
-		return "", fmt.Errorf("expecting 2 environments, got %d", len(opts.list.Environments))
+// perform performs the promotion of all releases in given hops, recording the releases, files and pull requests
+// promoted in given result
+func (p *Promotion) perform(opts PerformOpts, result *Result) (*Result, error) {
+	if opts.stacked {
+		return p.performStacked(opts, result)
 	}
 
-	sourceEnv := opts.list.Environments[0]
-	targetEnv := opts.list.Environments[1]
+	var (
+		infos         []*PromotionInfo
+		promotedFiles []string
+	)
+	for _, list := range opts.hops {
+		info, files, err := p.writePromotedFiles(list, opts, result)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+		promotedFiles = append(promotedFiles, files...)
+	}
+
+	if len(promotedFiles) == 0 {
+		p.println("🤷 Nothing to promote!")
+		result.Status = StatusNothingToPromote
+		return result, nil
+	}
+	result.Status = StatusPromoted
+
+	if opts.localOnly {
+		return result, nil
+	}
+
+	info := combinePromotionInfos(infos)
+
+	pullRequest, err := p.publish(info, promotedFiles, getBranchName(info), "", opts)
+	if err != nil {
+		return nil, err
+	}
+	result.PullRequests = append(result.PullRequests, pullRequest)
+
+	return result, p.complete(opts)
+}
+
+// performStacked performs each hop of the promotion in its own pull request, with the branch of each hop based on
+// the branch of the previous one
+func (p *Promotion) performStacked(opts PerformOpts, result *Result) (*Result, error) {
+	var base string
+
+	for _, list := range opts.hops {
+		info, files, err := p.writePromotedFiles(list, opts, result)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) == 0 {
+			continue
+		}
+		result.Status = StatusPromoted
+
+		if opts.localOnly {
+			continue
+		}
+
+		branchName := getBranchName(info)
+		pullRequest, err := p.publish(info, files, branchName, base, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.PullRequests = append(result.PullRequests, pullRequest)
+		base = branchName
+	}
+
+	if result.Status != StatusPromoted {
+		p.println("🤷 Nothing to promote!")
+		result.Status = StatusNothingToPromote
+		return result, nil
+	}
+
+	if opts.localOnly {
+		return result, nil
+	}
+
+	return result, p.complete(opts)
+}
+
+// writePromotedFiles writes the promoted files of given list and collects information about their releases,
+// returning the paths of files written and recording them in given result
+func (p *Promotion) writePromotedFiles(list cross.ReleaseList, opts PerformOpts, result *Result) (*PromotionInfo, []string, error) {
+	if len(list.Environments) != 2 {
+		return nil, nil, fmt.Errorf("expecting 2 environments, got %d", len(list.Environments))
+	}
+
+	sourceEnv := list.Environments[0]
+	targetEnv := list.Environments[1]
 
 	info := &PromotionInfo{
 		SourceEnvironment: sourceEnv,
@@ -51,12 +155,16 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 	}
 
 	var promotedFiles []string
-	for _, crossRelease := range opts.list.SortedCrossReleases() {
+	// Promote releases after the releases they depend on, so that they are listed in that order in the pull request
+	for _, crossRelease := range list.DependencyOrderedCrossReleases() {
 		promotedFile := crossRelease.PromotedFile
 		if promotedFile == nil {
 			continue
 		}
 		promotedFiles = append(promotedFiles, promotedFile.Path)
+		result.Files = append(result.Files, promotedFile.Path)
+
+		releaseResult := newReleaseResult(crossRelease, sourceEnv.Name, targetEnv.Name)
 
 		sourceRelease := crossRelease.Releases[0]
 		targetRelease := crossRelease.Releases[1]
@@ -68,11 +176,12 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 			p.printf("ℹ️ Dry-run: skipping writing promoted release %s to: %s\n", style.Resource(crossRelease.Name), style.SecondaryInfo(promotedFile.Path))
 		} else {
 			if err := p.YamlWriter.WriteFile(promotedFile); err != nil {
-				return "", fmt.Errorf("writing release %q promoted target yaml to file %q: %w", crossRelease.Name, promotedFile.Path, err)
+				return nil, nil, fmt.Errorf("writing release %q promoted target yaml to file %q: %w", crossRelease.Name, promotedFile.Path, err)
 			}
 		}
 
 		if opts.localOnly {
+			result.Releases = append(result.Releases, releaseResult)
 			continue
 		}
 
@@ -87,23 +196,34 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 			}
 		}
 
+		if releaseInfo.Error != nil {
+			releaseResult.Error = releaseInfo.Error.Error()
+		}
+		result.Releases = append(result.Releases, releaseResult)
+
 		info.Releases = append(info.Releases, releaseInfo)
 		info.Error = errors.Join(info.Error, releaseInfo.Error)
 	}
 
-	if len(promotedFiles) == 0 {
-		p.println("🤷 Nothing to promote!")
-		return "", nil
-	}
-
-	if opts.localOnly {
-		return "", nil
-	}
+	return info, promotedFiles, nil
+}
 
+// publish commits given files to a new branch and creates a pull request for it against given base branch,
+// returning a description of the pull request, without URL in dry-run and local-only modes
+func (p *Promotion) publish(info *PromotionInfo, promotedFiles []string, branchName, base string, opts PerformOpts) (PullRequestResult, error) {
 	commitTemplate := cmp.Or(opts.commitTemplate, defaultCommitAndPRTemplate)
 	commitMessage, err := renderMessage(commitTemplate, info)
 	if err != nil {
-		return "", fmt.Errorf("rendering commit message: %w", err)
+		return PullRequestResult{}, fmt.Errorf("rendering commit message: %w", err)
+	}
+
+	result := PullRequestResult{
+		Source:        info.SourceEnvironment.Name,
+		Target:        info.TargetEnvironment.Name,
+		Branch:        branchName,
+		Base:          base,
+		CommitMessage: commitMessage,
+		Draft:         opts.draft,
 	}
 
 	modeName := "normal"
@@ -114,7 +234,6 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 		modeName = "Local-Only"
 	}
 
-	branchName := getBranchName(info)
 	if opts.dryRun || opts.localOnly {
 		p.printf("ℹ️ %s: skipping creation of branch %s\nFiles:\n%s\nCommit message:\n%s\n",
 			modeName,
@@ -123,17 +242,12 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 	} else {
 		err = p.GitProvider.CreateAndPushBranchWithFiles(branchName, promotedFiles, commitMessage)
 		if err != nil {
-			return "", err
+			return PullRequestResult{}, err
 		}
 		p.PromptProvider.PrintBranchCreated(branchName, commitMessage)
 	}
 
-	var labels []string
-	labels = append(labels, "environment:"+info.TargetEnvironment.Name)
-	for _, release := range info.Releases {
-		labels = append(labels, "release:"+release.Name)
-	}
-
+	labels := getLabels(info)
 	if opts.autoMerge {
 		labels = append(labels, "auto-merge")
 	}
@@ -141,7 +255,7 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 	pullRequestTemplate := cmp.Or(opts.pullRequestTemplate, defaultCommitAndPRTemplate)
 	prMessage, err := renderMessage(pullRequestTemplate, info)
 	if err != nil {
-		return "", fmt.Errorf("rendering pull request message: %w", err)
+		return PullRequestResult{}, fmt.Errorf("rendering pull request message: %w", err)
 	}
 	prLines := strings.SplitN(prMessage, "\n", 2)
 	prTitle := prLines[0]
@@ -150,6 +264,10 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 		prBody = prLines[1]
 	}
 
+	if overridden := getOverriddenGates(info, opts.overriddenGates); len(overridden) > 0 {
+		prBody += renderOverriddenGates(overridden)
+	}
+
 	inferredReviewers := getReviewers(info)
 	reviewers := MergeUnique(inferredReviewers, opts.reviewers)
 
@@ -159,12 +277,12 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 			style.SecondaryInfo(prTitle), style.SecondaryInfo(prBody),
 			style.SecondaryInfo("- "+strings.Join(reviewers, "\n- ")),
 			style.SecondaryInfo("- "+strings.Join(labels, "\n- ")))
-		p.PromptProvider.PrintCompleted()
-		return "", nil
+		return result, nil
 	}
 
 	prURL, err := p.PullRequestProvider.Create(pr.CreateParams{
 		Branch:    branchName,
+		Base:      base,
 		Title:     prTitle,
 		Body:      prBody,
 		Labels:    labels,
@@ -172,7 +290,7 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 		Reviewers: reviewers,
 	})
 	if err != nil {
-		return "", fmt.Errorf("creating pull request: %w", err)
+		return PullRequestResult{}, fmt.Errorf("creating pull request: %w", err)
 	}
 
 	if opts.draft {
@@ -181,13 +299,85 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 		p.PromptProvider.PrintPullRequestCreated(prURL)
 	}
 
-	if err := p.GitProvider.CheckoutMasterBranch(); err != nil {
-		return "", fmt.Errorf("checking out master: %w", err)
+	result.URL = prURL
+	return result, nil
+}
+
+// complete restores the master branch once pull requests were created and prints completion message
+func (p *Promotion) complete(opts PerformOpts) error {
+	if !opts.dryRun {
+		if err := p.GitProvider.CheckoutMasterBranch(); err != nil {
+			return fmt.Errorf("checking out master: %w", err)
+		}
 	}
 
 	p.PromptProvider.PrintCompleted()
+	return nil
+}
+
+// combinePromotionInfos combines the promotion infos of multiple hops into a single one spanning from the source
+// environment of the first hop to the target environment of the last hop. Releases promoted across multiple hops
+// are reported with their information from the last hop.
+func combinePromotionInfos(infos []*PromotionInfo) *PromotionInfo {
+	if len(infos) == 1 {
+		return infos[0]
+	}
+
+	combined := &PromotionInfo{
+		SourceEnvironment: infos[0].SourceEnvironment,
+		TargetEnvironment: infos[len(infos)-1].TargetEnvironment,
+		Variables:         infos[0].Variables,
+	}
 
-	return prURL, nil
+	indexes := make(map[string]int)
+	for _, info := range infos {
+		if len(info.Releases) == 0 {
+			continue
+		}
+		combined.Hops = append(combined.Hops, info)
+		combined.Error = errors.Join(combined.Error, info.Error)
+
+		for _, release := range info.Releases {
+			if index, ok := indexes[release.Name]; ok {
+				combined.Releases[index] = release
+				continue
+			}
+			indexes[release.Name] = len(combined.Releases)
+			combined.Releases = append(combined.Releases, release)
+		}
+	}
+
+	return combined
+}
+
+// getOverriddenGates returns the given overridden gate violations relevant to the target environments of given info
+func getOverriddenGates(info *PromotionInfo, violations []GateViolation) []GateViolation {
+	targets := []string{info.TargetEnvironment.Name}
+	for _, hop := range info.Hops {
+		targets = append(targets, hop.TargetEnvironment.Name)
+	}
+
+	var result []GateViolation
+	for _, violation := range violations {
+		if slices.Contains(targets, violation.Environment) {
+			result = append(result, violation)
+		}
+	}
+	return result
+}
+
+func getLabels(info *PromotionInfo) []string {
+	var labels []string
+	if len(info.Hops) == 0 {
+		labels = append(labels, "environment:"+info.TargetEnvironment.Name)
+	}
+	for _, hop := range info.Hops {
+		labels = append(labels, "environment:"+hop.TargetEnvironment.Name)
+	}
+	for _, release := range info.Releases {
+		labels = append(labels, "release:"+release.Name)
+	}
+	return labels
 }
 
 func getReviewers(info *PromotionInfo) []string {
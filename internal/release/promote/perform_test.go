@@ -163,6 +163,57 @@ func TestRenderMessage(t *testing.T) {
 	require.Equal(t, expectedMessage, message)
 }
 
+func TestRenderDefaultMessage(t *testing.T) {
+	newEnvironment := func(name string) *v1alpha1.Environment {
+		return &v1alpha1.Environment{EnvironmentMetadata: v1alpha1.EnvironmentMetadata{ObjectMeta: metav1.ObjectMeta{Name: name}}}
+	}
+	dev, staging, prod := newEnvironment("dev"), newEnvironment("staging"), newEnvironment("prod")
+
+	info := combinePromotionInfos([]*PromotionInfo{
+		{
+			SourceEnvironment: dev,
+			TargetEnvironment: staging,
+			Releases:          []*ReleaseInfo{{Name: "api"}, {Name: "worker"}},
+		},
+		{
+			SourceEnvironment: staging,
+			TargetEnvironment: prod,
+			Releases:          []*ReleaseInfo{{Name: "api"}},
+		},
+	})
+
+	message, err := renderMessage(defaultCommitAndPRTemplate, info)
+	require.NoError(t, err)
+	require.Equal(t, `Promote 2 releases (dev -> prod)
+
+Promotion path:
+- dev -> staging: api, worker
+- staging -> prod: api`, message)
+
+	message, err = renderMessage(defaultCommitAndPRTemplate, &PromotionInfo{
+		SourceEnvironment: dev,
+		TargetEnvironment: staging,
+		Releases:          []*ReleaseInfo{{Name: "api"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Promote 1 releases (dev -> staging)", message)
+
+	message, err = renderMessage(defaultCommitAndPRTemplate, &PromotionInfo{
+		SourceEnvironment: staging,
+		TargetEnvironment: prod,
+		Releases: []*ReleaseInfo{
+			{Name: "db"},
+			{Name: "cache"},
+			{Name: "api", DependsOn: []string{"db", "cache"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `Promote 3 releases (staging -> prod)
+
+Dependencies (releases are promoted after the releases they depend on):
+- api depends on db, cache`, message)
+}
+
 func TestGetReviewers(t *testing.T) {
 	require.Equal(
 		t,
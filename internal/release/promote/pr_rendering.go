@@ -37,6 +37,7 @@ type ReleaseInfo struct {
 	ChangeType          ChangeType
 	Commits             []*CommitInfo
 	RelatedPullRequests []*info.PullRequest
+	DependsOn           []string
 	Error               error
 }
 
@@ -53,6 +54,10 @@ type PromotionInfo struct {
 	Releases          []*ReleaseInfo
 	Variables         map[string]string
 	Error             error
+
+	// Hops contains the promotion info of each hop when promoting along a path of more than two environments
+	// within a single pull request, and is empty otherwise.
+	Hops []*PromotionInfo
 }
 
 type CommitInfo struct {
@@ -126,6 +131,7 @@ func getReleaseInfo(cross *cross.Release, sourceRelease, targetRelease *v1alpha1
 		ValuesChanged: cross.PromotedFile != nil && !cross.ValuesInSync,
 		ChangeType:    changeType,
 		Commits:       []*CommitInfo{},
+		DependsOn:     sourceRelease.Spec.DependsOn,
 		Error:         nil,
 	}
 
@@ -138,7 +144,7 @@ func getReleaseInfo(cross *cross.Release, sourceRelease, targetRelease *v1alpha1
 	})
 	if err != nil {
 		// We still want to continue if there is failure
-		fmt.Printf("⚠️ Failed to clone %s repository attempts: %v\n", repository, err)
+		_, _ = fmt.Fprintf(opts.out, "⚠️ Failed to clone %s repository attempts: %v\n", repository, err)
 		releaseInfo.Error = fmt.Errorf("getting project source dir: %w", err)
 		return &releaseInfo, nil
 	}
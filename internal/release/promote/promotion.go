@@ -3,7 +3,9 @@ package promote
 import (
 	"fmt"
 	"io"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/nestoca/joy/api/v1alpha1"
 	"github.com/nestoca/joy/internal/git/pr"
@@ -26,6 +28,9 @@ type Promotion struct {
 	InfoProvider        info.Provider
 	LinksProvider       links.Provider
 	Out                 io.Writer
+
+	// Now returns the current time against which promotion gates are evaluated (defaults to time.Now).
+	Now func() time.Time
 }
 
 type Opts struct {
@@ -75,11 +80,26 @@ type Opts struct {
 
 	// Reviewers are additional reviewers to add to the PR
 	Reviewers []string
+
+	// Path is the ordered list of environments to promote through, one hop at a time, starting with the source
+	// environment and ending with the final target environment. When specified, SourceEnv and TargetEnv are ignored.
+	Path []*v1alpha1.Environment
+
+	// Stacked indicates that each hop of Path must be promoted in its own pull request, stacked onto the previous one,
+	// instead of a single combined pull request.
+	Stacked bool
+
+	// OverrideGates are the names of promotion gates to override, recording the override in the pull request.
+	OverrideGates []string
+
+	// IncludeDependencies indicates that out of sync releases that selected releases depend on must be promoted along
+	// with them, instead of only warning about them.
+	IncludeDependencies bool
 }
 
 // Promote prompts user to select source and target environments and releases to promote and creates a pull request,
-// returning its URL if any.
-func (p *Promotion) Promote(opts Opts) (string, error) {
+// returning a structured description of the promotion.
+func (p *Promotion) Promote(opts Opts) (*Result, error) {
 	if opts.DryRun {
 		p.println("ℹ️  Dry-run mode enabled: No changes will be made.")
 	}
@@ -88,42 +108,37 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 		p.println("ℹ️  Local-only mode enabled: The local repo will be modified, but not committed. No pull request will be created.")
 	}
 
-	// Prompt user to select source environment
-	if opts.SourceEnv == nil {
-		sourceEnvs, err := getSourceEnvironments(opts.SelectedEnvironments)
-		if err != nil {
-			return "", err
-		}
-		opts.SourceEnv, err = p.PromptProvider.SelectSourceEnvironment(sourceEnvs)
-		if err != nil {
-			return "", err
-		}
+	path, err := p.getPromotionPath(&opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// Prompt user to select target environment
-	if opts.TargetEnv == nil {
-		targetEnvs, err := getTargetEnvironments(opts.SelectedEnvironments, opts.SourceEnv)
-		if err != nil {
-			return "", err
-		}
-		opts.TargetEnv, err = p.PromptProvider.SelectTargetEnvironment(targetEnvs)
-		if err != nil {
-			return "", err
-		}
+	result := &Result{
+		Source:       path[0].Name,
+		Target:       path[len(path)-1].Name,
+		DryRun:       opts.DryRun,
+		LocalOnly:    opts.LocalOnly,
+		Releases:     []ReleaseResult{},
+		Files:        []string{},
+		PullRequests: []PullRequestResult{},
 	}
 
-	if !opts.TargetEnv.Spec.Promotion.AllowAutoMerge && opts.AutoMerge {
-		return "", fmt.Errorf("auto-merge is not allowed for target environment %s", opts.TargetEnv.Name)
-	}
+	for i := 1; i < len(path); i++ {
+		sourceEnv, targetEnv := path[i-1], path[i]
 
-	// Validate promotability (only relevant if either or both environments were specified via command line flags)
-	if !opts.SourceEnv.IsPromotableTo(opts.TargetEnv) {
-		return "", fmt.Errorf("environment %s is not promotable to %s", opts.SourceEnv.Name, opts.TargetEnv.Name)
+		if !targetEnv.Spec.Promotion.AllowAutoMerge && opts.AutoMerge {
+			return nil, fmt.Errorf("auto-merge is not allowed for target environment %s", targetEnv.Name)
+		}
+
+		// Validate promotability (only relevant if either or both environments were specified via command line flags)
+		if !sourceEnv.IsPromotableTo(targetEnv) {
+			return nil, fmt.Errorf("environment %s is not promotable to %s", sourceEnv.Name, targetEnv.Name)
+		}
 	}
 
-	list, err := opts.Catalog.Releases.GetReleasesForPromotion(opts.SourceEnv, opts.TargetEnv)
+	list, err := opts.Catalog.Releases.GetReleasesForPromotion(path[0], path[1])
 	if err != nil {
-		return "", fmt.Errorf("getting releases for promotion: %w", err)
+		return nil, fmt.Errorf("getting releases for promotion: %w", err)
 	}
 
 	selectedList, err := func() (cross.ReleaseList, error) {
@@ -136,41 +151,67 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 		return p.PromptProvider.SelectReleases(list, opts.MaxColumnWidth)
 	}()
 	if err != nil {
-		return "", fmt.Errorf("selecting releases to promote: %w", err)
+		return nil, fmt.Errorf("selecting releases to promote: %w", err)
 	}
 
 	selectedList, err = selectedList.RemoveReleasesByName(opts.Omit)
 	if err != nil {
-		return "", fmt.Errorf("omitting releases: %w", err)
+		return nil, fmt.Errorf("omitting releases: %w", err)
 	}
 
+	selectedList = p.withDependencies(list, selectedList, opts.IncludeDependencies, opts.Omit)
+
 	if opts.KeepPrerelease {
-		selectedList = selectedList.Filter(func(release *cross.Release) bool {
-			return len(release.Releases) != 2 || release.Releases[1] == nil || !IsPrerelease(release.Releases[1])
-		})
+		selectedList = withoutPrereleaseTargets(selectedList)
+	}
+
+	// Chain subsequent hops of the path onto the releases selected for the first one
+	hops := []cross.ReleaseList{selectedList}
+	for _, targetEnv := range path[2:] {
+		hop, err := hops[len(hops)-1].GetReleasesForNextPromotion(opts.Catalog.Releases, targetEnv)
+		if err != nil {
+			return nil, fmt.Errorf("getting releases for promotion to %s: %w", targetEnv.Name, err)
+		}
+		if opts.KeepPrerelease {
+			hop = withoutPrereleaseTargets(hop)
+		}
+		hops = append(hops, hop)
 	}
 
-	if !selectedList.HasAnyPromotableReleases() {
+	if !slices.ContainsFunc(hops, func(hop cross.ReleaseList) bool { return hop.HasAnyPromotableReleases() }) {
 		p.PromptProvider.PrintNoPromotableReleasesFound(opts.ReleasesFiltered, opts.SourceEnv, opts.TargetEnv)
-		return "", nil
+		result.Status = StatusNothingToPromote
+		return result, nil
 	}
 
-	invalidList := selectedList.GetNonPromotableReleases(opts.SourceEnv, opts.TargetEnv)
-	if len(invalidList) != 0 {
-		invalid := strings.Join(invalidList, ", ")
-		p.PromptProvider.PrintSelectedNonPromotableReleases(invalid, opts.TargetEnv.Name)
-		return "", fmt.Errorf("cannot promote releases with non-standard version to %s environment", opts.TargetEnv.Name)
+	for _, hop := range hops {
+		sourceEnv, targetEnv := hop.Environments[0], hop.Environments[1]
+		invalidList := hop.GetNonPromotableReleases(sourceEnv, targetEnv)
+		if len(invalidList) != 0 {
+			invalid := strings.Join(invalidList, ", ")
+			p.PromptProvider.PrintSelectedNonPromotableReleases(invalid, targetEnv.Name)
+			return nil, fmt.Errorf("cannot promote releases with non-standard version to %s environment", targetEnv.Name)
+		}
+	}
+
+	overriddenGates, ownerReviewers, err := p.checkGates(hops, opts)
+	if err != nil {
+		return nil, err
 	}
+	opts.Reviewers = MergeUnique(opts.Reviewers, ownerReviewers)
 
 	if !opts.NoPrompt {
-		if err := p.preview(selectedList); err != nil {
-			return "", fmt.Errorf("previewing: %w", err)
+		for _, hop := range hops {
+			if err := p.preview(hop); err != nil {
+				return nil, fmt.Errorf("previewing: %w", err)
+			}
 		}
 	}
 
 	// There's a previous check so only one option can be true at a time
 	performParams := PerformOpts{
-		list:                selectedList,
+		hops:                hops,
+		stacked:             opts.Stacked,
 		autoMerge:           opts.AutoMerge,
 		draft:               opts.Draft,
 		dryRun:              opts.DryRun,
@@ -181,23 +222,26 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 		infoProvider:        p.InfoProvider,
 		linksProvider:       p.LinksProvider,
 		reviewers:           opts.Reviewers,
+		overriddenGates:     overriddenGates,
+		out:                 p.Out,
 	}
 
 	if opts.NoPrompt || opts.LocalOnly {
-		return p.perform(performParams)
+		return p.perform(performParams, result)
 	}
 
 	if opts.AutoMerge || opts.Draft {
 		confirmed, err := p.PromptProvider.ConfirmCreatingPromotionPullRequest(opts.AutoMerge, opts.Draft)
 		if err != nil {
-			return "", fmt.Errorf("confirming creating promotion pull request: %w", err)
+			return nil, fmt.Errorf("confirming creating promotion pull request: %w", err)
 		}
 		if !confirmed {
 			p.PromptProvider.PrintCanceled()
-			return "", nil
+			result.Status = StatusCanceled
+			return result, nil
 		}
 
-		return p.perform(performParams)
+		return p.perform(performParams, result)
 	}
 
 loop:
@@ -205,7 +249,7 @@ loop:
 		// Prompt user to select creating a pull request
 		action, err := p.PromptProvider.SelectPromotionAction()
 		if err != nil {
-			return "", fmt.Errorf("selecting create promotion pull request: %w", err)
+			return nil, fmt.Errorf("selecting create promotion pull request: %w", err)
 		}
 
 		switch action {
@@ -215,34 +259,119 @@ loop:
 			performParams.draft = true
 			break loop
 		case ViewGitLog:
-			for _, crossRelease := range selectedList.Items {
-				p.println(style.SecondaryInfo("--- " + crossRelease.Name))
-				source, target := at(crossRelease.Releases, sourceEnvIndex), at(crossRelease.Releases, targetEnvIndex)
-
-				info, err := getReleaseInfo(crossRelease, source, target, performParams)
-				if err != nil {
-					p.printf("error getting release information %s: %v\n", crossRelease.Name, err)
-					continue
-				}
-
-				for _, commit := range info.Commits {
-					p.printf("%s %s %s\n", style.Resource(commit.ShortSha), style.Author(commit.GitHubAuthor), style.SecondaryInfo(commit.ShortMessage))
-				}
-
-				if len(info.Commits) == 0 {
-					p.println("no commits found in repo")
+			for _, hop := range hops {
+				for _, crossRelease := range hop.Items {
+					header := "--- " + crossRelease.Name
+					if len(hops) > 1 {
+						header += fmt.Sprintf(" (%s -> %s)", hop.Environments[0].Name, hop.Environments[1].Name)
+					}
+					p.println(style.SecondaryInfo(header))
+					source, target := at(crossRelease.Releases, sourceEnvIndex), at(crossRelease.Releases, targetEnvIndex)
+
+					info, err := getReleaseInfo(crossRelease, source, target, performParams)
+					if err != nil {
+						p.printf("error getting release information %s: %v\n", crossRelease.Name, err)
+						continue
+					}
+
+					for _, commit := range info.Commits {
+						p.printf("%s %s %s\n", style.Resource(commit.ShortSha), style.Author(commit.GitHubAuthor), style.SecondaryInfo(commit.ShortMessage))
+					}
+
+					if len(info.Commits) == 0 {
+						p.println("no commits found in repo")
+					}
+
+					p.println()
 				}
-
-				p.println()
 			}
 
 		case Cancel:
 			p.PromptProvider.PrintCanceled()
-			return "", nil
+			result.Status = StatusCanceled
+			return result, nil
 		}
 	}
 
-	return p.perform(performParams)
+	return p.perform(performParams, result)
+}
+
+// checkGates evaluates the promotion gates of target environments, printing which gate blocks which release, and
+// returns an error if any violation is not explicitly overridden. It otherwise returns the overridden violations and
+// the project owners required as reviewers.
+func (p *Promotion) checkGates(hops []cross.ReleaseList, opts Opts) ([]GateViolation, []string, error) {
+	if err := ValidateGateNames(opts.OverrideGates); err != nil {
+		return nil, nil, err
+	}
+
+	violations, ownerReviewers, err := p.evaluateGates(hops, opts.Catalog.Releases)
+	if err != nil {
+		return nil, nil, fmt.Errorf("evaluating promotion gates: %w", err)
+	}
+
+	var overridden []GateViolation
+	blocked := 0
+	for _, violation := range violations {
+		if slices.Contains(opts.OverrideGates, violation.Gate) {
+			p.printf("⚠️ Overriding gate %s of environment %s for %s: %s\n", style.Resource(violation.Gate), style.Resource(violation.Environment), violation.subject(), violation.Reason)
+			overridden = append(overridden, violation)
+			continue
+		}
+		p.printf("⛔ Gate %s of environment %s blocks %s: %s\n", style.Resource(violation.Gate), style.Resource(violation.Environment), violation.subject(), violation.Reason)
+		blocked++
+	}
+
+	if blocked > 0 {
+		return nil, nil, fmt.Errorf("promotion blocked by %d gate violation(s), use --override-gate to override", blocked)
+	}
+
+	return overridden, ownerReviewers, nil
+}
+
+// getPromotionPath returns the environments to promote through, which is either the explicit path specified in
+// options or the source and target environments, prompting user to select them when not specified.
+func (p *Promotion) getPromotionPath(opts *Opts) ([]*v1alpha1.Environment, error) {
+	if len(opts.Path) > 0 {
+		if len(opts.Path) < 2 {
+			return nil, fmt.Errorf("promotion path must contain at least 2 environments, got %d", len(opts.Path))
+		}
+		opts.SourceEnv = opts.Path[0]
+		opts.TargetEnv = opts.Path[len(opts.Path)-1]
+		return opts.Path, nil
+	}
+
+	// Prompt user to select source environment
+	if opts.SourceEnv == nil {
+		sourceEnvs, err := getSourceEnvironments(opts.SelectedEnvironments)
+		if err != nil {
+			return nil, err
+		}
+		opts.SourceEnv, err = p.PromptProvider.SelectSourceEnvironment(sourceEnvs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Prompt user to select target environment
+	if opts.TargetEnv == nil {
+		targetEnvs, err := getTargetEnvironments(opts.SelectedEnvironments, opts.SourceEnv)
+		if err != nil {
+			return nil, err
+		}
+		opts.TargetEnv, err = p.PromptProvider.SelectTargetEnvironment(targetEnvs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return []*v1alpha1.Environment{opts.SourceEnv, opts.TargetEnv}, nil
+}
+
+// withoutPrereleaseTargets returns the releases of given list whose target release is not a prerelease.
+func withoutPrereleaseTargets(list cross.ReleaseList) cross.ReleaseList {
+	return list.Filter(func(release *cross.Release) bool {
+		return len(release.Releases) != 2 || release.Releases[1] == nil || !IsPrerelease(release.Releases[1])
+	})
 }
 
 func (p *Promotion) preview(list cross.ReleaseList) error {
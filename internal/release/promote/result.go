@@ -0,0 +1,92 @@
+package promote
+
+import (
+	"github.com/nestoca/joy/internal/release/cross"
+	"github.com/nestoca/joy/internal/yml"
+)
+
+type Status string
+
+const (
+	// StatusPromoted means that promoted release files were written, and a pull request created unless in dry-run
+	// or local-only mode.
+	StatusPromoted Status = "promoted"
+
+	// StatusNothingToPromote means that selected releases were already in sync.
+	StatusNothingToPromote Status = "nothing-to-promote"
+
+	// StatusCanceled means that the user canceled the promotion.
+	StatusCanceled Status = "canceled"
+)
+
+// Result describes the outcome of a promotion in a structured form, so that tools and CI pipelines do not need to
+// parse the human-readable output.
+type Result struct {
+	Status    Status `json:"status" yaml:"status"`
+	Source    string `json:"source" yaml:"source"`
+	Target    string `json:"target" yaml:"target"`
+	DryRun    bool   `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+	LocalOnly bool   `json:"localOnly,omitempty" yaml:"localOnly,omitempty"`
+
+	// Releases are the releases promoted, once per hop when promoting along a path.
+	Releases []ReleaseResult `json:"releases" yaml:"releases"`
+
+	// Files are the paths of release files written.
+	Files []string `json:"files" yaml:"files"`
+
+	// PullRequests are the pull requests created, or that would have been created in dry-run mode. There is a single
+	// one unless stacked pull requests were requested along a path.
+	PullRequests []PullRequestResult `json:"pullRequests" yaml:"pullRequests"`
+}
+
+type ReleaseResult struct {
+	Name             string `json:"name" yaml:"name"`
+	Source           string `json:"source" yaml:"source"`
+	Target           string `json:"target" yaml:"target"`
+	FromVersion      string `json:"fromVersion,omitempty" yaml:"fromVersion,omitempty"`
+	ToVersion        string `json:"toVersion" yaml:"toVersion"`
+	FromChartVersion string `json:"fromChartVersion,omitempty" yaml:"fromChartVersion,omitempty"`
+	ToChartVersion   string `json:"toChartVersion,omitempty" yaml:"toChartVersion,omitempty"`
+	File             string `json:"file" yaml:"file"`
+
+	// Error is the error encountered collecting information about the release, which does not prevent promotion.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type PullRequestResult struct {
+	Source        string `json:"source" yaml:"source"`
+	Target        string `json:"target" yaml:"target"`
+	Branch        string `json:"branch" yaml:"branch"`
+	Base          string `json:"base,omitempty" yaml:"base,omitempty"`
+	CommitMessage string `json:"commitMessage" yaml:"commitMessage"`
+	URL           string `json:"url,omitempty" yaml:"url,omitempty"`
+	Draft         bool   `json:"draft,omitempty" yaml:"draft,omitempty"`
+}
+
+// PullRequestURL returns the URL of the last pull request created, if any.
+func (r *Result) PullRequestURL() string {
+	if len(r.PullRequests) == 0 {
+		return ""
+	}
+	return r.PullRequests[len(r.PullRequests)-1].URL
+}
+
+func newReleaseResult(crossRelease *cross.Release, sourceEnv, targetEnv string) ReleaseResult {
+	// Versions are read from the promoted file, as the chart version may be locked in target environment
+	promoted := crossRelease.PromotedFile
+
+	result := ReleaseResult{
+		Name:           crossRelease.Name,
+		Source:         sourceEnv,
+		Target:         targetEnv,
+		ToVersion:      yml.FindNodeValueOrDefault(promoted.Tree, "spec.version", ""),
+		ToChartVersion: yml.FindNodeValueOrDefault(promoted.Tree, "spec.chart.version", ""),
+		File:           promoted.Path,
+	}
+	if target := crossRelease.Releases[1]; target != nil {
+		result.FromVersion = target.Spec.Version
+		result.FromChartVersion = target.Spec.Chart.Version
+	}
+
+	return result
+}
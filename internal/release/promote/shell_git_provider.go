@@ -2,8 +2,10 @@ package promote
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/nestoca/joy/internal/git"
+	"github.com/nestoca/joy/internal/yml"
 )
 
 type ShellGitProvider struct {
@@ -39,3 +41,36 @@ func (g *ShellGitProvider) CreateAndPushBranchWithFiles(branchName string, files
 func (g *ShellGitProvider) CheckoutMasterBranch() error {
 	return git.Checkout(g.dir, "master")
 }
+
+// GetVersionIntroductionTime returns the time of the commit that changed the version of given release file to given
+// version, walking its history back for as long as the version is unchanged, or zero time if the most recent commit
+// of the file does not have that version.
+func (g *ShellGitProvider) GetVersionIntroductionTime(file string, version string) (time.Time, error) {
+	entries, err := git.GetFileLog(g.dir, file)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	commits := make([]string, len(entries))
+	for i, entry := range entries {
+		commits[i] = entry.Commit
+	}
+
+	contents, err := git.ShowFileRevisions(g.dir, file, commits)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var introduced time.Time
+	for i, entry := range entries {
+		revision, err := yml.NewFile(file, contents[i])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parsing %s at %s: %w", file, entry.Commit, err)
+		}
+		if yml.FindNodeValueOrDefault(revision.Tree, "spec.version", "") != version {
+			break
+		}
+		introduced = entry.Time
+	}
+	return introduced, nil
+}
@@ -0,0 +1,53 @@
+package promote
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellGitProviderGetVersionIntroductionTime(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "releases", "api.yaml")
+
+	day := func(day int) time.Time {
+		return time.Date(2024, time.March, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	git := func(date time.Time, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=john", "-c", "user.email=john@acme.com"}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date.Format(time.RFC3339), "GIT_COMMITTER_DATE="+date.Format(time.RFC3339))
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	commitFile := func(date time.Time, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+		git(date, "add", "-A")
+		git(date, "commit", "-m", "Update api")
+	}
+
+	git(day(1), "init", "-q")
+	commitFile(day(1), "spec:\n  version: 1.1.0\n")
+	commitFile(day(2), "spec:\n  version: 1.0.0\n")
+	commitFile(day(3), "spec:\n  version: 1.1.0\n")
+	// Mentioning the version elsewhere in the file does not change the version
+	commitFile(day(4), "spec:\n  version: 1.1.0\n  values:\n    image:\n      tag: 1.1.0\n")
+
+	provider := NewShellGitProvider(dir)
+
+	introduced, err := provider.GetVersionIntroductionTime(file, "1.1.0")
+	require.NoError(t, err)
+	require.True(t, day(3).Equal(introduced), "expected %s but got %s", day(3), introduced)
+
+	introduced, err = provider.GetVersionIntroductionTime(file, "1.0.0")
+	require.NoError(t, err)
+	require.True(t, introduced.IsZero())
+}
@@ -6,6 +6,7 @@ import (
 	"maps"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -322,6 +323,193 @@ func TestPromotion(t *testing.T) {
 			expectedErrorMessage:    "",
 			expectedPromoted:        true,
 		},
+		{
+			name: "Promote release1 along path from dev to prod in single pull request",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Catalog.Environments[stagingEnvIndex].Spec.Promotion.FromEnvironments = []string{"dev"}
+				opts.Path = opts.Catalog.Environments
+				opts.All = true
+				opts.Catalog.Releases.Items = opts.Catalog.Releases.Items[:1]
+
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				crossRel0.Releases[devEnvIndex] = newRelease("release1", `spec:
+  values:
+    env:
+      ENV_VAR: value1`, "dev")
+				crossRel0.Releases[stagingEnvIndex] = newRelease("release1", `spec:
+  values:
+    env:
+      ENV_VAR: value2`, sourceEnvName)
+				crossRel0.Releases[prodEnvIndex] = newRelease("release1", `spec:
+  values:
+    env:
+      ENV_VAR: value3`, targetEnvName)
+
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+
+				args.yamlWriter.WriteFileFunc = func(file *yml.File) error {
+					return nil
+				}
+
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.PrintReleasePreviewCalls(), 2)
+
+					writeCalls := args.yamlWriter.WriteFileCalls()
+					require.Len(t, writeCalls, 2)
+					require.Equal(t, "/dummy/environments/staging/releases/release.yaml", writeCalls[0].File.Path)
+					require.Equal(t, "/dummy/environments/prod/releases/release.yaml", writeCalls[1].File.Path)
+					promotedYaml, err := writeCalls[1].File.Yaml()
+					require.NoError(t, err)
+					require.Contains(t, string(promotedYaml), "ENV_VAR: value1")
+
+					require.Len(t, args.gitProvider.CreateAndPushBranchWithFilesCalls(), 1)
+
+					createCalls := args.prProvider.CreateCalls()
+					require.Len(t, createCalls, 1)
+					require.Equal(t, "PR: Promote 1 releases (dev -> prod)", createCalls[0].CreateParams.Title)
+					require.Equal(t, "", createCalls[0].CreateParams.Base)
+					require.Equal(t, []string{"environment:staging", "environment:prod", "release:release1"}, createCalls[0].CreateParams.Labels)
+				}
+			},
+			commitTemplate:      simpleCommitTemplate,
+			pullRequestTemplate: simplePullRequestTemplate,
+			expectedPromoted:    true,
+		},
+		{
+			name: "Promote release1 along path from dev to prod in stacked pull requests",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Catalog.Environments[stagingEnvIndex].Spec.Promotion.FromEnvironments = []string{"dev"}
+				opts.Path = opts.Catalog.Environments
+				opts.Stacked = true
+				opts.All = true
+				opts.Catalog.Releases.Items = opts.Catalog.Releases.Items[:1]
+
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				crossRel0.Releases[devEnvIndex] = newRelease("release1", `spec:
+  values:
+    env:
+      ENV_VAR: value1`, "dev")
+
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+
+				args.yamlWriter.WriteFileFunc = func(file *yml.File) error {
+					return nil
+				}
+
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/" + createParams.Branch, nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					branchCalls := args.gitProvider.CreateAndPushBranchWithFilesCalls()
+					require.Len(t, branchCalls, 2)
+
+					createCalls := args.prProvider.CreateCalls()
+					require.Len(t, createCalls, 2)
+					require.Equal(t, "PR: Promote 1 releases (dev -> staging)", createCalls[0].CreateParams.Title)
+					require.Equal(t, "", createCalls[0].CreateParams.Base)
+					require.Equal(t, "PR: Promote 1 releases (staging -> prod)", createCalls[1].CreateParams.Title)
+					require.Equal(t, branchCalls[0].BranchName, createCalls[1].CreateParams.Base)
+
+					require.Len(t, args.gitProvider.CheckoutMasterBranchCalls(), 1)
+					require.Len(t, args.promptProvider.PrintCompletedCalls(), 1)
+				}
+			},
+			commitTemplate:      simpleCommitTemplate,
+			pullRequestTemplate: simplePullRequestTemplate,
+			expectedPromoted:    true,
+		},
+		{
+			name: "Promotion blocked by gates of target environment",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.All = true
+				opts.Catalog.Releases.Items = opts.Catalog.Releases.Items[:1]
+				opts.TargetEnv.Spec.Promotion.FromPullRequests = true
+				opts.TargetEnv.Spec.Promotion.Gates = v1alpha1.PromotionGates{
+					MinHoursInSource: 24,
+					NoPrerelease:     true,
+				}
+
+				sourceRelease := newRelease("release1", `spec:
+  version: 1.2.4-rc.1`, sourceEnvName)
+				sourceRelease.Spec.Version = "1.2.4-rc.1"
+				opts.Catalog.Releases.Items[0].Releases[sourceEnvIndex] = sourceRelease
+
+				args.gitProvider.GetVersionIntroductionTimeFunc = func(file string, version string) (time.Time, error) {
+					return time.Now().Add(-2 * time.Hour), nil
+				}
+
+				return func(t *testing.T) {
+					require.Len(t, args.gitProvider.GetVersionIntroductionTimeCalls(), 1)
+					require.Equal(t, "1.2.4-rc.1", args.gitProvider.GetVersionIntroductionTimeCalls()[0].Version)
+					require.Empty(t, args.prProvider.CreateCalls())
+				}
+			},
+			expectedErrorMessage: "promotion blocked by 2 gate violation(s), use --override-gate to override",
+			expectedPromoted:     false,
+		},
+		{
+			name: "Promotion with overridden gate recorded in pull request",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.All = true
+				opts.NoPrompt = true
+				opts.OverrideGates = []string{promote.GateNoPrerelease}
+				opts.Catalog.Releases.Items = opts.Catalog.Releases.Items[:1]
+				opts.TargetEnv.Spec.Promotion.FromPullRequests = true
+				opts.TargetEnv.Spec.Promotion.Gates = v1alpha1.PromotionGates{
+					NoPrerelease:          true,
+					RequireOwnerReviewers: true,
+				}
+
+				sourceRelease := newRelease("release1", `spec:
+  version: 1.2.4-rc.1`, sourceEnvName)
+				sourceRelease.Spec.Version = "1.2.4-rc.1"
+				sourceRelease.Project = &v1alpha1.Project{
+					ProjectMetadata: v1alpha1.ProjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "project1"}},
+					Spec:            v1alpha1.ProjectSpec{Repository: "owner/project1", Owners: []string{"team-a"}},
+				}
+				opts.Catalog.Releases.Items[0].Releases[sourceEnvIndex] = sourceRelease
+
+				args.yamlWriter.WriteFileFunc = func(file *yml.File) error {
+					return nil
+				}
+
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					createCalls := args.prProvider.CreateCalls()
+					require.Len(t, createCalls, 1)
+					require.Contains(t, createCalls[0].CreateParams.Body, "# Overridden promotion gates")
+					require.Contains(t, createCalls[0].CreateParams.Body, "| noPrerelease | prod | release release1 | version 1.2.4-rc.1 is a prerelease |")
+					require.Contains(t, createCalls[0].CreateParams.Reviewers, "team-a")
+				}
+			},
+			expectedPromoted: true,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -356,7 +544,7 @@ func TestPromotion(t *testing.T) {
 				LinksProvider:       linksProvider,
 				Out:                 io.Discard,
 			}
-			prURL, err := promotion.Promote(c.opts)
+			result, err := promotion.Promote(c.opts)
 
 			// Check expected results
 			if c.expectedErrorMessage != "" {
@@ -365,14 +553,95 @@ func TestPromotion(t *testing.T) {
 				assert.NoError(t, err)
 			}
 			if c.expectedPromoted {
-				assert.NotEmpty(t, prURL)
-			} else {
-				assert.Empty(t, prURL)
+				require.NotNil(t, result)
+				assert.NotEmpty(t, result.PullRequestURL())
+			} else if result != nil {
+				assert.Empty(t, result.PullRequestURL())
 			}
 		})
 	}
 }
 
+func TestPromotionResult(t *testing.T) {
+	opts := newOpts()
+	opts.NoPrompt = true
+	opts.Releases = []string{"release1"}
+
+	crossRel0 := opts.Catalog.Releases.Items[0]
+	crossRel0.Releases[sourceEnvIndex] = newRelease("release1", `spec:
+  version: 1.1.0
+  chart:
+    version: 2.0.0
+  values:
+    key: value1`, sourceEnvName)
+	crossRel0.Releases[targetEnvIndex] = newRelease("release1", `spec:
+  version: 1.0.0
+  chart:
+    version: !lock 1.0.0
+  values:
+    key: value2`, targetEnvName)
+	crossRel0.Releases[sourceEnvIndex].Spec.Version = "1.1.0"
+	crossRel0.Releases[targetEnvIndex].Spec.Version = "1.0.0"
+	crossRel0.Releases[targetEnvIndex].Spec.Chart.Version = "1.0.0"
+
+	infoProvider := new(info.ProviderMock)
+	setupDefaultMockInfoProvider(infoProvider)
+	infoProvider.GetCommitsMetadataFunc = func(projectDir string, fromTag string, toTag string) ([]*info.CommitMetadata, error) {
+		return nil, fmt.Errorf("unknown tag")
+	}
+
+	promotion := promote.Promotion{
+		PromptProvider: new(promote.PromptProviderMock),
+		GitProvider:    new(promote.GitProviderMock),
+		PullRequestProvider: &pr.PullRequestProviderMock{
+			CreateFunc: func(createParams pr.CreateParams) (string, error) {
+				return "https://github.com/owner/repo/pull/123", nil
+			},
+		},
+		YamlWriter:     &yml.WriterMock{WriteFileFunc: func(file *yml.File) error { return nil }},
+		CommitTemplate: "Promote {{ len .Releases }} releases",
+		InfoProvider:   infoProvider,
+		LinksProvider:  new(links.ProviderMock),
+		Out:            io.Discard,
+	}
+
+	result, err := promotion.Promote(opts)
+	require.NoError(t, err)
+
+	require.Len(t, result.PullRequests, 1)
+	require.Contains(t, result.PullRequests[0].Branch, "promote-release1-from-staging-to-prod-")
+	result.PullRequests[0].Branch = ""
+
+	require.Equal(t, &promote.Result{
+		Status: promote.StatusPromoted,
+		Source: "staging",
+		Target: "prod",
+		Releases: []promote.ReleaseResult{
+			{
+				Name:             "release1",
+				Source:           "staging",
+				Target:           "prod",
+				FromVersion:      "1.0.0",
+				ToVersion:        "1.1.0",
+				FromChartVersion: "1.0.0",
+				ToChartVersion:   "1.0.0",
+				File:             "/dummy/environments/prod/releases/release.yaml",
+				Error:            "getting commits metadata: unknown tag",
+			},
+		},
+		Files: []string{"/dummy/environments/prod/releases/release.yaml"},
+		PullRequests: []promote.PullRequestResult{
+			{
+				Source:        "staging",
+				Target:        "prod",
+				CommitMessage: "Promote 1 releases\n\n# Rendering Errors: getting commits metadata: unknown tag",
+				URL:           "https://github.com/owner/repo/pull/123",
+			},
+		},
+	}, result)
+	require.Equal(t, "https://github.com/owner/repo/pull/123", result.PullRequestURL())
+}
+
 func newEnvironment(name string, promotionSourceEnvs ...string) *v1alpha1.Environment {
 	return &v1alpha1.Environment{
 		EnvironmentMetadata: v1alpha1.EnvironmentMetadata{ObjectMeta: metav1.ObjectMeta{Name: name}},
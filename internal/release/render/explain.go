@@ -0,0 +1,322 @@
+package render
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+type ValueSource string
+
+const (
+	// SourceRelease is for values set by the values of the release.
+	SourceRelease ValueSource = "release"
+
+	// SourceEnvironment is for values referenced from the values of the environment with $ref().
+	SourceEnvironment ValueSource = "environment"
+
+	// SourceMapping is for values set by a chart mapping, which only apply to values the release does not set.
+	SourceMapping ValueSource = "mapping"
+
+	// SourceChartDefault is for values set by neither the release nor chart mappings, namely defaults of the chart
+	// schema or of its values.yaml file.
+	SourceChartDefault ValueSource = "chart default"
+)
+
+// ValueOrigin describes where a leaf of the hydrated values of a release came from.
+type ValueOrigin struct {
+	// Path is the dot-separated path of the value, with dots within keys escaped.
+	Path  string
+	Value any
+
+	Source ValueSource
+
+	// File and Line locate where the value is set, when known.
+	File string
+	Line int
+
+	// Mapping is the key of the chart mapping setting the value, for mapping values.
+	Mapping string
+
+	// Reference is the $ref() expression of the release referencing the value, for environment values.
+	Reference string
+
+	// Expression is the template the value was rendered from, if any.
+	Expression string
+}
+
+// Describe describes the origin of the value, with file paths relative to given directory.
+func (origin ValueOrigin) Describe(dir string) string {
+	var description string
+	switch origin.Source {
+	case SourceMapping:
+		description = "chart mapping " + origin.Mapping
+	case SourceEnvironment:
+		description = "environment via " + origin.Reference
+	default:
+		description = string(origin.Source)
+	}
+
+	if origin.File != "" {
+		file := origin.File
+		if rel, err := filepath.Rel(dir, file); err == nil && dir != "" {
+			file = rel
+		}
+		if origin.Line > 0 {
+			file += fmt.Sprintf(":%d", origin.Line)
+		}
+		description += " (" + file + ")"
+	}
+
+	if origin.Expression != "" {
+		description += " from template " + origin.Expression
+	}
+
+	return description
+}
+
+// ExplainValues hydrates the values of given release, as rendering it would, and merges them over the default values
+// of the chart, as helm would, returning the origin of each of their leaves sorted by path. Lists are considered leaves.
+func ExplainValues(release *v1alpha1.Release, chart *helm.ChartFS) ([]ValueOrigin, error) {
+	values, err := HydrateValues(release, chart)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := map[string]any{}
+	var defaultsTree *yaml.Node
+	if chart != nil {
+		if defaults, defaultsTree, err = readChartDefaults(chart); err != nil {
+			return nil, err
+		}
+	}
+
+	var origins []ValueOrigin
+	walkLeaves(mergeValues(defaults, values), nil, func(path []string, value any) {
+		var origin ValueOrigin
+		if hasPath(values, path) {
+			origin = explainValue(release, chart, path)
+		} else {
+			origin = ValueOrigin{Source: SourceChartDefault, File: "values.yaml", Line: defaultsLine(defaultsTree, path)}
+		}
+		origin.Path = joinPath(path)
+		origin.Value = value
+		origins = append(origins, origin)
+	})
+
+	return origins, nil
+}
+
+// FilterValueOrigins returns the origins of the values at or below given dot-separated path, all of them if the path
+// is empty or ".".
+func FilterValueOrigins(origins []ValueOrigin, path string) []ValueOrigin {
+	if path == "" || path == "." {
+		return origins
+	}
+	return slices.DeleteFunc(slices.Clone(origins), func(origin ValueOrigin) bool {
+		return origin.Path != path && !strings.HasPrefix(origin.Path, path+".")
+	})
+}
+
+// FormatValueOrigins formats value origins one per line, with file paths relative to given directory.
+func FormatValueOrigins(origins []ValueOrigin, dir string) string {
+	var builder strings.Builder
+	for _, origin := range origins {
+		_, _ = fmt.Fprintf(&builder, "%s: %s  # %s\n", origin.Path, formatValue(origin.Value), origin.Describe(dir))
+	}
+	return builder.String()
+}
+
+// formatValue formats given value as compact JSON, which unlike YAML distinguishes strings from other scalars.
+func formatValue(value any) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func explainValue(release *v1alpha1.Release, chart *helm.ChartFS, path []string) ValueOrigin {
+	// Release values come first, as chart mappings only apply to values the release does not set
+	var current any = release.Spec.Values
+	for i, key := range path {
+		values, ok := asValuesMap(current)
+		if !ok {
+			break
+		}
+		if current, ok = values[key]; !ok {
+			break
+		}
+
+		if reference, ok := current.(string); ok {
+			if matches := objectValuesRegex.FindStringSubmatch(reference); len(matches) > 0 && matches[1] == "ref" {
+				envPath := append(strings.Split(strings.TrimPrefix(matches[2], objectValuesSupportedPrefix), "."), path[i+1:]...)
+				return explainEnvironmentValue(release.Environment, envPath, strings.TrimSpace(reference))
+			}
+		}
+
+		if i == len(path)-1 {
+			return ValueOrigin{
+				Source:     SourceRelease,
+				File:       filePath(release.File),
+				Line:       valuesLine(fileTree(release.File), path),
+				Expression: templateExpression(current),
+			}
+		}
+	}
+
+	if chart != nil {
+		// The longest mapping key is the most specific one
+		var keys []string
+		for key := range chart.Mappings {
+			if segments := yml.SplitIntoPathSegments(key); len(segments) <= len(path) && slices.Equal(segments, path[:len(segments)]) {
+				keys = append(keys, key)
+			}
+		}
+		slices.SortFunc(keys, func(a, b string) int { return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b)) })
+
+		if len(keys) > 0 {
+			origin := ValueOrigin{
+				Source:     SourceMapping,
+				Mapping:    keys[0],
+				Expression: templateExpression(chart.Mappings[keys[0]]),
+			}
+			// Mappings of the release itself override those of the chart reference of the catalog
+			if _, ok := release.Spec.Chart.Mappings[keys[0]]; ok && release.File != nil {
+				if node, err := yml.FindNode(release.File.Tree, "spec.chart.mappings."+yml.EscapePathSegment(keys[0])); err == nil {
+					origin.File = release.File.Path
+					origin.Line = node.Line
+				}
+			}
+			return origin
+		}
+	}
+
+	return ValueOrigin{Source: SourceChartDefault}
+}
+
+func explainEnvironmentValue(env *v1alpha1.Environment, path []string, reference string) ValueOrigin {
+	origin := ValueOrigin{Source: SourceEnvironment, Reference: reference}
+	if env == nil {
+		return origin
+	}
+
+	var current any = env.Spec.Values
+	for _, key := range path {
+		values, ok := asValuesMap(current)
+		if !ok {
+			current = nil
+			break
+		}
+		current = values[key]
+	}
+
+	origin.File = filePath(env.File)
+	origin.Line = valuesLine(fileTree(env.File), path)
+	origin.Expression = templateExpression(current)
+
+	return origin
+}
+
+// walkLeaves calls given function for each leaf of given values, in order of path.
+func walkLeaves(value any, path []string, fn func(path []string, value any)) {
+	values, ok := asValuesMap(value)
+	if !ok || (len(values) == 0 && len(path) > 0) {
+		fn(slices.Clone(path), value)
+		return
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		walkLeaves(values[key], append(path, key), fn)
+	}
+}
+
+// hasPath reports whether given values contain a value at given path.
+func hasPath(values any, path []string) bool {
+	for _, key := range path {
+		valuesMap, ok := asValuesMap(values)
+		if !ok {
+			return false
+		}
+		if values, ok = valuesMap[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// defaultsLine returns the line of the value at given path within the tree of the values.yaml file of a chart, or of
+// its closest parent if not found, or 0 if there is no such file.
+func defaultsLine(tree *yaml.Node, path []string) int {
+	if tree == nil {
+		return 0
+	}
+	for i := len(path); i > 0; i-- {
+		if node, err := yml.FindNode(tree, joinPath(path[:i])); err == nil {
+			return node.Line
+		}
+	}
+	return 0
+}
+
+func asValuesMap(value any) (map[string]any, bool) {
+	switch value := value.(type) {
+	case map[string]any:
+		return value, true
+	case map[any]any:
+		result := make(map[string]any, len(value))
+		for key, subValue := range value {
+			result[fmt.Sprint(key)] = subValue
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+func templateExpression(value any) string {
+	if text, ok := value.(string); ok && strings.Contains(text, "{{") {
+		return text
+	}
+	return ""
+}
+
+func joinPath(path []string) string {
+	segments := make([]string, len(path))
+	for i, segment := range path {
+		segments[i] = yml.EscapePathSegment(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+func filePath(file *yml.File) string {
+	if file == nil {
+		return ""
+	}
+	return file.Path
+}
+
+func fileTree(file *yml.File) *yaml.Node {
+	if file == nil {
+		return nil
+	}
+	return file.Tree
+}
@@ -0,0 +1,104 @@
+package render
+
+import (
+	"os"
+	"testing"
+
+	"github.com/davidmdm/x/xfs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+func TestExplainValues(t *testing.T) {
+	envFile, err := yml.NewFile("/catalog/environments/staging/env.yaml", []byte(`apiVersion: joy.nesto.ca/v1alpha1
+kind: Environment
+metadata:
+  name: staging
+spec:
+  values:
+    database:
+      host: db.staging
+      port: 5432
+`))
+	require.NoError(t, err)
+
+	env, err := v1alpha1.NewEnvironment(envFile)
+	require.NoError(t, err)
+
+	releaseFile, err := yml.NewFile("/catalog/environments/staging/releases/api.yaml", []byte(`apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  version: 1.2.3
+  chart:
+    mappings:
+      image.repository: acme/api
+  values:
+    replicas: !lock 2
+    condition: x > 1
+    host: '{{ .Environment.Name }}.acme.com'
+    database: $ref(.Environment.Spec.Values.database)
+`))
+	require.NoError(t, err)
+
+	release, err := v1alpha1.LoadRelease(releaseFile)
+	require.NoError(t, err)
+	release.Environment = env
+
+	chart := &helm.ChartFS{
+		Chart: helm.Chart{
+			Mappings: map[string]any{
+				"image.repository": "acme/api",
+				"image.tag":        "{{ .Release.Spec.Version }}",
+				"replicas":         1,
+			},
+		},
+		FS: &xfs.FSMock{
+			ReadFileFunc: func(name string) ([]byte, error) {
+				switch name {
+				case "values.cue":
+					return []byte(`#values: { debug: bool | *false, ... }`), nil
+				case "values.yaml":
+					return []byte("replicas: 1\nimage:\n  pullPolicy: IfNotPresent\nresources:\n  limits:\n    cpu: 100m\n"), nil
+				default:
+					return nil, os.ErrNotExist
+				}
+			},
+		},
+	}
+
+	origins, err := ExplainValues(release, chart)
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		`condition: "x > 1"  # release (environments/staging/releases/api.yaml:12)
+database.host: "db.staging"  # environment via $ref(.Environment.Spec.Values.database) (environments/staging/env.yaml:8)
+database.port: 5432  # environment via $ref(.Environment.Spec.Values.database) (environments/staging/env.yaml:9)
+debug: false  # chart default
+host: "staging.acme.com"  # release (environments/staging/releases/api.yaml:13) from template {{ .Environment.Name }}.acme.com
+image.pullPolicy: "IfNotPresent"  # chart default (values.yaml:3)
+image.repository: "acme/api"  # chart mapping image.repository (environments/staging/releases/api.yaml:9)
+image.tag: "1.2.3"  # chart mapping image.tag from template {{ .Release.Spec.Version }}
+replicas: 2  # release (environments/staging/releases/api.yaml:11)
+resources.limits.cpu: "100m"  # chart default (values.yaml:6)
+`,
+		FormatValueOrigins(origins, "/catalog"),
+	)
+
+	require.Equal(
+		t,
+		[]string{"image.pullPolicy", "image.repository", "image.tag"},
+		func() []string {
+			var paths []string
+			for _, origin := range FilterValueOrigins(origins, "image") {
+				paths = append(paths, origin.Path)
+			}
+			return paths
+		}(),
+	)
+}
@@ -0,0 +1,141 @@
+package render
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nestoca/joy/internal/text"
+)
+
+// Resource is a Kubernetes resource of a rendered manifest.
+type Resource struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Content is the normalized yaml of the resource, with keys sorted and comments removed, such that it can be
+	// compared regardless of how it was rendered.
+	Content string
+}
+
+// ID identifies the resource within a manifest, by kind, namespace and name.
+func (resource Resource) ID() string {
+	if resource.Namespace == "" {
+		return resource.Kind + "/" + resource.Name
+	}
+	return resource.Kind + "/" + resource.Namespace + "/" + resource.Name
+}
+
+// ParseManifest parses the resources of a multi-document manifest, sorted by kind, namespace and name. Empty documents
+// are skipped.
+func ParseManifest(manifest string) ([]Resource, error) {
+	var resources []Resource
+
+	decoder := yaml.NewDecoder(strings.NewReader(manifest))
+	for index := 0; ; index++ {
+		var document map[string]any
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decoding document %d: %w", index, err)
+		}
+		if len(document) == 0 {
+			continue
+		}
+
+		content, err := yaml.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("encoding document %d: %w", index, err)
+		}
+
+		resource := Resource{Content: string(content)}
+		resource.Kind, _ = document["kind"].(string)
+		if metadata, ok := document["metadata"].(map[string]any); ok {
+			resource.Name, _ = metadata["name"].(string)
+			resource.Namespace, _ = metadata["namespace"].(string)
+		}
+		resources = append(resources, resource)
+	}
+
+	slices.SortStableFunc(resources, func(a, b Resource) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	return resources, nil
+}
+
+// ResourceDiff is the diff of a resource between two manifests.
+type ResourceDiff struct {
+	ID string
+
+	// Diff is the unified diff of the resource, empty if unchanged.
+	Diff string
+}
+
+// DiffManifests compares the resources of two manifests by kind, namespace and name, regardless of the order of
+// resources and of their keys. It returns the diffs of all resources found in either manifest, sorted by ID.
+func DiffManifests(from, to text.File, context int, diff text.DiffFunc) ([]ResourceDiff, error) {
+	fromResources, err := ParseManifest(from.Content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", from.Name, err)
+	}
+
+	toResources, err := ParseManifest(to.Content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", to.Name, err)
+	}
+
+	fromContents := resourceContents(fromResources)
+	toContents := resourceContents(toResources)
+
+	var ids []string
+	for id := range fromContents {
+		ids = append(ids, id)
+	}
+	for id := range toContents {
+		if _, ok := fromContents[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	diffs := make([]ResourceDiff, 0, len(ids))
+	for _, id := range ids {
+		result := ResourceDiff{ID: id}
+		if fromContents[id] != toContents[id] {
+			result.Diff = diff(
+				text.File{Name: to.Name + " " + id, Content: toContents[id]},
+				text.File{Name: from.Name + " " + id, Content: fromContents[id]},
+				context,
+			)
+		}
+		diffs = append(diffs, result)
+	}
+
+	return diffs, nil
+}
+
+// resourceContents returns the contents of resources by ID. Resources sharing the same ID, which should not happen in
+// valid manifests, are concatenated so that none of them is lost.
+func resourceContents(resources []Resource) map[string]string {
+	contents := make(map[string]string, len(resources))
+	for _, resource := range resources {
+		if existing, ok := contents[resource.ID()]; ok {
+			contents[resource.ID()] = existing + "---\n" + resource.Content
+			continue
+		}
+		contents[resource.ID()] = resource.Content
+	}
+	return contents
+}
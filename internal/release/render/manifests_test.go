@@ -0,0 +1,93 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal/text"
+)
+
+func TestParseManifest(t *testing.T) {
+	resources, err := ParseManifest(`---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: prod
+---
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 2
+`)
+	require.NoError(t, err)
+
+	require.Len(t, resources, 2)
+	require.Equal(t, "Deployment/app", resources[0].ID())
+	require.Equal(t, "Service/prod/app", resources[1].ID())
+	require.Equal(t, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n    name: app\nspec:\n    replicas: 2\n", resources[0].Content)
+}
+
+func TestParseManifestInvalid(t *testing.T) {
+	_, err := ParseManifest("kind: [")
+	require.ErrorContains(t, err, "decoding document 0")
+}
+
+func TestDiffManifests(t *testing.T) {
+	from := text.File{Name: "staging", Content: `
+kind: Service
+metadata:
+  name: app
+---
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+---
+kind: ConfigMap
+metadata:
+  name: removed
+`}
+
+	// Same service with keys and resources in a different order, a changed deployment and an added secret
+	to := text.File{Name: "prod", Content: `
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+---
+metadata:
+  name: app
+kind: Service
+---
+kind: Secret
+metadata:
+  name: added
+`}
+
+	diffs, err := DiffManifests(from, to, 0, text.Diff)
+	require.NoError(t, err)
+
+	var ids, changed []string
+	for _, diff := range diffs {
+		ids = append(ids, diff.ID)
+		if diff.Diff != "" {
+			changed = append(changed, diff.ID)
+		}
+	}
+
+	require.Equal(t, []string{"ConfigMap/removed", "Deployment/app", "Secret/added", "Service/app"}, ids)
+	require.Equal(t, []string{"ConfigMap/removed", "Deployment/app", "Secret/added"}, changed)
+
+	require.Contains(t, diffs[1].Diff, "--- staging Deployment/app")
+	require.Contains(t, diffs[1].Diff, "+++ prod Deployment/app")
+	require.Contains(t, diffs[1].Diff, "-      replicas: 1")
+	require.Contains(t, diffs[1].Diff, "+      replicas: 3")
+}
@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
 	cueerrors "cuelang.org/go/cue/errors"
+	cuejson "cuelang.org/go/encoding/json"
+	"cuelang.org/go/encoding/jsonschema"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/davidmdm/x/xerr"
@@ -83,8 +86,10 @@ func HydrateValues(release *v1alpha1.Release, chart *helm.ChartFS) (map[string]a
 		return nil, fmt.Errorf("hydrating object values: %w", err)
 	}
 
-	for key, value := range chart.Mappings {
-		setInMap(values, yml.SplitIntoPathSegments(key), value)
+	if chart != nil {
+		for key, value := range chart.Mappings {
+			setInMap(values, yml.SplitIntoPathSegments(key), value)
+		}
 	}
 
 	data, err := yaml.Marshal(values)
@@ -107,7 +112,12 @@ func HydrateValues(release *v1alpha1.Release, chart *helm.ChartFS) (map[string]a
 		return nil, err
 	}
 
-	result, err = unifyValues(result, chart)
+	var tree *yaml.Node
+	if release.File != nil {
+		tree = release.File.Tree
+	}
+
+	result, err = unifyValues(result, chart, tree)
 	if err != nil {
 		return nil, fmt.Errorf("unifying with chart schema: %w", err)
 	}
@@ -115,11 +125,27 @@ func HydrateValues(release *v1alpha1.Release, chart *helm.ChartFS) (map[string]a
 	return result, nil
 }
 
-func unifyValues(values map[string]any, chart *helm.ChartFS) (map[string]any, error) {
+// unifyValues unifies values with the CUE schema of the chart, if any, which may also provide their defaults, and validates
+// the result against the JSON schema of the chart, if any. Errors are reported with the line of the values in the
+// release file they relate to, when known.
+func unifyValues(values map[string]any, chart *helm.ChartFS, tree *yaml.Node) (map[string]any, error) {
 	if chart == nil {
 		return values, nil
 	}
 
+	values, err := unifyCueSchema(values, chart, tree)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateJSONSchema(values, chart, tree); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func unifyCueSchema(values map[string]any, chart *helm.ChartFS, tree *yaml.Node) (map[string]any, error) {
 	rawSchema, err := chart.ReadFile("values.cue")
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -137,7 +163,7 @@ func unifyValues(values map[string]any, chart *helm.ChartFS) (map[string]any, er
 	unified := schema.Unify(value)
 
 	if err := unified.Validate(cue.Final(), cue.Concrete(true)); err != nil {
-		return nil, xerr.MultiErrFrom("validating values", AsErrorList(cueerrors.Errors(err))...)
+		return nil, xerr.MultiErrFrom("validating values", withValuesLines(err, tree)...)
 	}
 
 	var result map[string]any
@@ -148,6 +174,139 @@ func unifyValues(values map[string]any, chart *helm.ChartFS) (map[string]any, er
 	return result, nil
 }
 
+// validateJSONSchema validates values against the values.schema.json file of the chart, if any. As with helm, values
+// are validated after being merged over the default values of the chart, but are otherwise left untouched.
+func validateJSONSchema(values map[string]any, chart *helm.ChartFS, tree *yaml.Node) error {
+	rawSchema, err := chart.ReadFile("values.schema.json")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading values.schema.json: %w", err)
+	}
+
+	expr, err := cuejson.Extract("values.schema.json", rawSchema)
+	if err != nil {
+		return fmt.Errorf("parsing values.schema.json: %w", err)
+	}
+
+	ctx := cuecontext.New()
+
+	// Schemas that cannot be converted to CUE, such as those referencing external schemas, are left for helm to
+	// validate when rendering, which it does with the schema as is.
+	file, err := jsonschema.Extract(ctx.BuildExpr(expr), &jsonschema.Config{})
+	if err != nil {
+		return nil
+	}
+
+	schema := ctx.BuildFile(file)
+	if schema.Err() != nil {
+		return nil
+	}
+
+	defaults, _, err := readChartDefaults(chart)
+	if err != nil {
+		return err
+	}
+
+	unified := schema.Unify(ctx.Encode(mergeValues(defaults, values)))
+
+	if err := unified.Validate(cue.Final(), cue.Concrete(true)); err != nil {
+		return xerr.MultiErrFrom("validating values against values.schema.json", withValuesLines(err, tree)...)
+	}
+
+	return nil
+}
+
+// readChartDefaults returns the default values of the chart from its values.yaml file, if any, along with the tree of
+// that file for locating them.
+func readChartDefaults(chart *helm.ChartFS) (map[string]any, *yaml.Node, error) {
+	defaults := map[string]any{}
+
+	rawDefaults, err := chart.ReadFile("values.yaml")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return defaults, nil, nil
+		}
+		return nil, nil, fmt.Errorf("reading chart values.yaml: %w", err)
+	}
+
+	var tree yaml.Node
+	if err := yaml.Unmarshal(rawDefaults, &tree); err != nil {
+		return nil, nil, fmt.Errorf("parsing chart values.yaml: %w", err)
+	}
+	if tree.Kind == 0 {
+		// Empty file
+		return defaults, nil, nil
+	}
+	if err := tree.Decode(&defaults); err != nil {
+		return nil, nil, fmt.Errorf("parsing chart values.yaml: %w", err)
+	}
+
+	return defaults, &tree, nil
+}
+
+// mergeValues returns the values merged over the default values, recursing into maps present in both.
+func mergeValues(defaults, values map[string]any) map[string]any {
+	result := make(map[string]any, len(defaults)+len(values))
+	for key, value := range defaults {
+		result[key] = value
+	}
+	for key, value := range values {
+		defaultMap, isDefaultMap := result[key].(map[string]any)
+		valueMap, isValueMap := value.(map[string]any)
+		if isDefaultMap && isValueMap {
+			result[key] = mergeValues(defaultMap, valueMap)
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// withValuesLines splits a CUE validation error into its individual errors, prefixing each with the line of the
+// closest release value it relates to, if found in given release file tree.
+func withValuesLines(err error, tree *yaml.Node) []error {
+	var result []error
+	for _, err := range cueerrors.Errors(err) {
+		if line := valuesLine(tree, err.Path()); line > 0 {
+			result = append(result, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		result = append(result, err)
+	}
+	return result
+}
+
+// valuesLine returns the line of the release value at given CUE path, or of its closest parent when the value itself
+// cannot be found, such as when it is missing or within a list. It returns zero if none is found.
+func valuesLine(tree *yaml.Node, path []string) int {
+	if tree == nil {
+		return 0
+	}
+
+	// Skip the definition the schema is looked up from, such as #values
+	for len(path) > 0 && strings.HasPrefix(path[0], "#") {
+		path = path[1:]
+	}
+
+	segments := []string{"spec", "values"}
+	for _, selector := range path {
+		if unquoted, err := strconv.Unquote(selector); err == nil {
+			selector = unquoted
+		}
+		segments = append(segments, yml.EscapePathSegment(selector))
+	}
+
+	for i := len(segments); i >= 2; i-- {
+		if node, err := yml.FindNode(tree, strings.Join(segments[:i], ".")); err == nil {
+			return node.Line
+		}
+	}
+
+	return 0
+}
+
 var objectValuesRegex = regexp.MustCompile(`^\s*\$(\w+)\(\s*((\.\w+)+)\s*\)\s*$`)
 
 const objectValuesSupportedPrefix = ".Environment.Spec.Values."
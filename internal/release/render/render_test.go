@@ -501,26 +501,50 @@ func TestHydrateObjectValues(t *testing.T) {
 }
 
 func TestSchemaUnification(t *testing.T) {
+	const jsonSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "replicas": {"type": "integer", "minimum": 1},
+    "image": {
+      "type": "object",
+      "properties": {
+        "repository": {"type": "string"},
+        "tag": {"type": "string"}
+      }
+    }
+  }
+}`
+
+	const releaseYAML = `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  values:
+    replicas: 0
+    image:
+      tag: 1.2
+`
+
 	cases := []struct {
 		Name           string
-		ReadFileFunc   func(string) ([]byte, error)
+		Files          map[string]string
 		Values         map[string]any
+		ReleaseYAML    string
 		ExpectedValues map[string]any
 		ExpectedError  string
 	}{
 		{
-			Name: "applies schema default",
-			ReadFileFunc: func(name string) ([]byte, error) {
-				return []byte(`#values: { color: "r" | "g" | *"b" }`), nil
-			},
+			Name:           "applies schema default",
+			Files:          map[string]string{"values.cue": `#values: { color: "r" | "g" | *"b" }`},
 			Values:         map[string]any{},
 			ExpectedValues: map[string]any{"color": "b"},
 		},
 		{
-			Name: "fails schema validation",
-			ReadFileFunc: func(name string) ([]byte, error) {
-				return []byte(`#values: { color: "r" | "g" | *"b" }`), nil
-			},
+			Name:   "fails schema validation",
+			Files:  map[string]string{"values.cue": `#values: { color: "r" | "g" | *"b" }`},
 			Values: map[string]any{"color": "cyan", "enabled": true},
 			ExpectedError: strings.Join(
 				[]string{
@@ -534,18 +558,58 @@ func TestSchemaUnification(t *testing.T) {
 			),
 		},
 		{
-			Name: "no schema file",
-			ReadFileFunc: func(name string) ([]byte, error) {
-				return nil, os.ErrNotExist
+			Name:        "reports lines of release values failing schema validation",
+			Files:       map[string]string{"values.cue": `#values: { replicas: int & >0, image: tag: string }`},
+			ReleaseYAML: releaseYAML,
+			ExpectedError: strings.Join(
+				[]string{
+					"unifying with chart schema: validating values:",
+					"  - line 7: #values.replicas: invalid value 0 (out of bound >0)",
+					"  - line 9: #values.image.tag: conflicting values string and 1.2 (mismatched types string and float)",
+				},
+				"\n",
+			),
+		},
+		{
+			Name:           "passes json schema validation with chart default values",
+			Files:          map[string]string{"values.schema.json": jsonSchema, "values.yaml": "image:\n  repository: acme/api\n"},
+			Values:         map[string]any{"replicas": 2, "image": map[string]any{"tag": "1.2"}},
+			ExpectedValues: map[string]any{"replicas": 2, "image": map[string]any{"tag": "1.2"}},
+		},
+		{
+			Name:          "fails json schema validation",
+			Files:         map[string]string{"values.schema.json": jsonSchema},
+			Values:        map[string]any{"replicas": 2},
+			ExpectedError: "unifying with chart schema: validating values against values.schema.json: image: field is required but not present",
+		},
+		{
+			Name:        "reports lines of release values failing json schema validation",
+			Files:       map[string]string{"values.schema.json": jsonSchema},
+			ReleaseYAML: releaseYAML,
+			ExpectedError: strings.Join(
+				[]string{
+					"unifying with chart schema: validating values against values.schema.json:",
+					"  - line 7: replicas: invalid value 0 (out of bound >=1)",
+					"  - line 9: image.tag: conflicting values string and 1.2 (mismatched types string and float)",
+				},
+				"\n",
+			),
+		},
+		{
+			Name: "skips json schema with external references",
+			Files: map[string]string{
+				"values.schema.json": `{"type": "object", "properties": {"image": {"$ref": "https://acme.com/schemas/image.json"}}}`,
 			},
+			Values:         map[string]any{"image": map[string]any{"tag": "1.2"}},
+			ExpectedValues: map[string]any{"image": map[string]any{"tag": "1.2"}},
+		},
+		{
+			Name:           "no schema file",
 			Values:         map[string]any{"color": "red"},
 			ExpectedValues: map[string]any{"color": "red"},
 		},
 		{
-			Name: "do not html escape strings",
-			ReadFileFunc: func(name string) ([]byte, error) {
-				return nil, os.ErrNotExist
-			},
+			Name:           "do not html escape strings",
 			Values:         map[string]any{"successCondition": "x > 100"},
 			ExpectedValues: map[string]any{"successCondition": "x > 100"},
 		},
@@ -553,15 +617,26 @@ func TestSchemaUnification(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.Name, func(t *testing.T) {
-			mockFS := &xfs.FSMock{ReadFileFunc: tc.ReadFileFunc}
-
-			result, err := HydrateValues(
-				&v1alpha1.Release{
-					Spec:        v1alpha1.ReleaseSpec{Values: tc.Values},
-					Environment: &v1alpha1.Environment{},
+			mockFS := &xfs.FSMock{
+				ReadFileFunc: func(name string) ([]byte, error) {
+					content, ok := tc.Files[name]
+					if !ok {
+						return nil, os.ErrNotExist
+					}
+					return []byte(content), nil
 				},
-				&helm.ChartFS{FS: mockFS},
-			)
+			}
+
+			release := &v1alpha1.Release{Spec: v1alpha1.ReleaseSpec{Values: tc.Values}}
+			if tc.ReleaseYAML != "" {
+				file, err := yml.NewFile("api.yaml", []byte(tc.ReleaseYAML))
+				require.NoError(t, err)
+				release, err = v1alpha1.LoadRelease(file)
+				require.NoError(t, err)
+			}
+			release.Environment = &v1alpha1.Environment{}
+
+			result, err := HydrateValues(release, &helm.ChartFS{FS: mockFS})
 
 			if tc.ExpectedError != "" {
 				require.EqualError(t, err, tc.ExpectedError)
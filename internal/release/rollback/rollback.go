@@ -0,0 +1,228 @@
+// Package rollback reverts a release of a given environment to a previous version found in the catalog git history.
+//
+// Only the version (and chart version) of the release are rewritten, in place, so that all other values and their
+// !lock/!local tags are preserved, and unrelated releases promoted alongside the bad version are left untouched.
+package rollback
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/google/uuid"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/git/pr"
+	"github.com/nestoca/joy/internal/release/history"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+const defaultTemplate = `Rollback {{ .Release.Name }} {{ .FromVersion }} -> {{ .ToVersion }} ({{ .Environment.Name }})
+
+Rolls back release {{ .Release.Name }} in environment {{ .Environment.Name }} from version {{ .FromVersion }} to version {{ .ToVersion }}
+{{- if ne .FromChartVersion .ToChartVersion }}, and its chart from version {{ .FromChartVersion }} to
+{{- if .ToChartVersion }} version {{ .ToChartVersion }}{{ else }} the default version of the environment{{ end }}{{ end }},
+as found in catalog commit {{ .Commit }}.
+`
+
+type Rollback struct {
+	HistoryProvider     history.Provider
+	GitProvider         promote.GitProvider
+	PullRequestProvider pr.PullRequestProvider
+	YamlWriter          yml.Writer
+	Template            string
+	Out                 io.Writer
+}
+
+type Opts struct {
+	// Catalog contains the release to roll back.
+	Catalog *catalog.Catalog
+
+	// Environment is the name of the environment in which to roll back the release.
+	Environment string
+
+	// Release is the name of the release to roll back.
+	Release string
+
+	// ToVersion is the version to roll back to. If empty, the version preceding the current one in history is used.
+	ToVersion string
+
+	// Draft indicates if PR created needs to be draft
+	Draft bool
+
+	// DryRun indicates if the rollback should be performed in dry-run mode
+	DryRun bool
+
+	// LocalOnly indicates if the rollback should only write the changes to the working tree without creating a branch,
+	// commit or pull request.
+	LocalOnly bool
+
+	// Reviewers are additional reviewers to add to the PR
+	Reviewers []string
+}
+
+// Info describes a rollback and is the data passed to the rollback template.
+type Info struct {
+	Release          *v1alpha1.Release
+	Environment      *v1alpha1.Environment
+	FromVersion      string
+	ToVersion        string
+	FromChartVersion string
+
+	// ToChartVersion is the chart version of the release rolled back to, which is empty if it did not pin any, in
+	// which case the chart version of the release is removed.
+	ToChartVersion string
+
+	// Commit is the catalog commit in which the version rolled back to was found.
+	Commit string
+}
+
+// Rollback rolls back given release to a previous version and creates a pull request, returning its URL if any.
+func (r *Rollback) Rollback(opts Opts) (string, error) {
+	release, err := opts.Catalog.LookupRelease(opts.Environment, opts.Release)
+	if err != nil {
+		return "", err
+	}
+
+	previous, commit, err := r.findPreviousRelease(release, opts.ToVersion)
+	if err != nil {
+		return "", err
+	}
+
+	info := &Info{
+		Release:          release,
+		Environment:      release.Environment,
+		FromVersion:      release.Spec.Version,
+		ToVersion:        previous.Spec.Version,
+		FromChartVersion: release.Spec.Chart.Version,
+		ToChartVersion:   previous.Spec.Chart.Version,
+		Commit:           commit,
+	}
+
+	if err := yml.SetOrAddNodeValue(release.File.Tree, "spec.version", info.ToVersion); err != nil {
+		return "", fmt.Errorf("setting version: %w", err)
+	}
+	if info.ToChartVersion != info.FromChartVersion {
+		if info.ToChartVersion == "" {
+			// Falls back to the chart version of the environment or default chart, as was the case at that revision
+			yml.RemoveNode(release.File.Tree, "spec.chart.version")
+		} else if err := yml.SetOrAddNodeValue(release.File.Tree, "spec.chart.version", info.ToChartVersion); err != nil {
+			return "", fmt.Errorf("setting chart version: %w", err)
+		}
+	}
+
+	r.printf("⏪ Rolling back release %s in environment %s from version %s to %s\n",
+		style.Resource(release.Name), style.Resource(info.Environment.Name), style.Version(info.FromVersion), style.Version(info.ToVersion))
+
+	if opts.DryRun {
+		r.printf("ℹ️ Dry-run: skipping writing release %s to: %s\n", style.Resource(release.Name), style.SecondaryInfo(release.File.Path))
+	} else {
+		if err := r.YamlWriter.WriteFile(release.File); err != nil {
+			return "", fmt.Errorf("writing release file %q: %w", release.File.Path, err)
+		}
+	}
+
+	if opts.LocalOnly {
+		return "", nil
+	}
+
+	message, err := renderMessage(cmp.Or(r.Template, defaultTemplate), info)
+	if err != nil {
+		return "", err
+	}
+	title, body, _ := strings.Cut(message, "\n")
+
+	branchName := fmt.Sprintf("rollback-%s-in-%s-to-%s-%s", release.Name, info.Environment.Name, info.ToVersion, uuid.New().String())
+	labels := []string{"environment:" + info.Environment.Name, "release:" + release.Name, "rollback"}
+
+	if opts.DryRun {
+		r.printf("ℹ️ Dry-run: skipping creation of branch %s and pull request:\n%s\n%s\nLabels:\n%s\n",
+			style.Resource(branchName), style.SecondaryInfo(title), style.SecondaryInfo(body),
+			style.SecondaryInfo("- "+strings.Join(labels, "\n- ")))
+		return "", nil
+	}
+
+	if err := r.GitProvider.CreateAndPushBranchWithFiles(branchName, []string{release.File.Path}, message); err != nil {
+		return "", err
+	}
+	r.printf("✅ Committed and pushed new branch %s\n", style.Resource(branchName))
+
+	var reviewers []string
+	if release.Project != nil {
+		reviewers = release.Project.Spec.Reviewers
+	}
+
+	prURL, err := r.PullRequestProvider.Create(pr.CreateParams{
+		Branch:    branchName,
+		Title:     title,
+		Body:      strings.TrimSpace(body),
+		Labels:    labels,
+		Draft:     opts.Draft,
+		Reviewers: promote.MergeUnique(reviewers, opts.Reviewers),
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating pull request: %w", err)
+	}
+	r.printf("✅ Created pull request: %s\n", prURL)
+
+	if err := r.GitProvider.CheckoutMasterBranch(); err != nil {
+		return "", fmt.Errorf("checking out master: %w", err)
+	}
+
+	return prURL, nil
+}
+
+// findPreviousRelease walks the history of given release file, from most recent to oldest, and returns the first
+// revision of the release with the given version, or with a different version than current one if not specified,
+// along with the commit of that revision.
+func (r *Rollback) findPreviousRelease(release *v1alpha1.Release, version string) (*v1alpha1.Release, string, error) {
+	revisions, err := r.HistoryProvider.GetFileRevisions(release.File.Path)
+	if err != nil {
+		return nil, "", fmt.Errorf("getting history of release %s: %w", release.Name, err)
+	}
+
+	for _, revision := range revisions {
+		file, err := yml.NewFile(release.File.Path, revision.Content)
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing release %s at commit %s: %w", release.Name, revision.Commit, err)
+		}
+
+		previous, err := v1alpha1.LoadRelease(file)
+		if err != nil {
+			return nil, "", fmt.Errorf("loading release %s at commit %s: %w", release.Name, revision.Commit, err)
+		}
+
+		if (version == "" && previous.Spec.Version != release.Spec.Version) || (version != "" && previous.Spec.Version == version) {
+			return previous, revision.Commit, nil
+		}
+	}
+
+	if version != "" {
+		return nil, "", fmt.Errorf("version %s not found in history of release %s", version, release.Name)
+	}
+	return nil, "", fmt.Errorf("no previous version found in history of release %s", release.Name)
+}
+
+func renderMessage(messageTemplate string, info *Info) (string, error) {
+	tmpl, err := template.New("message").Funcs(sprig.FuncMap()).Parse(messageTemplate)
+	if err != nil {
+		return "", fmt.Errorf("parsing rollback template: %w", err)
+	}
+
+	var message strings.Builder
+	if err := tmpl.Execute(&message, info); err != nil {
+		return "", fmt.Errorf("executing rollback template: %w", err)
+	}
+
+	return message.String(), nil
+}
+
+func (r *Rollback) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(r.Out, format, args...)
+}
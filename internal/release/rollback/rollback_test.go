@@ -0,0 +1,218 @@
+package rollback
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/git/pr"
+	"github.com/nestoca/joy/internal/release/cross"
+	"github.com/nestoca/joy/internal/release/history"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+const currentYAML = `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.3.0
+  chart:
+    ref: generic
+    version: 2.0.0
+  values:
+    replicas: !lock 3
+    env:
+      HOST: !local api.prod.acme.com
+      FEATURE: enabled
+`
+
+func newRevision(commit, version, chartVersion string) history.Revision {
+	chart := "    ref: generic\n"
+	if chartVersion != "" {
+		chart += "    version: " + chartVersion + "\n"
+	}
+	return history.Revision{
+		Commit: commit,
+		Content: []byte(`apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: ` + version + `
+  chart:
+` + chart + `  values:
+    replicas: 1
+`),
+	}
+}
+
+func newCatalog(t *testing.T) *catalog.Catalog {
+	file, err := yml.NewFile("/catalog/environments/prod/releases/api.yaml", []byte(currentYAML))
+	require.NoError(t, err)
+
+	release, err := v1alpha1.LoadRelease(file)
+	require.NoError(t, err)
+
+	env := &v1alpha1.Environment{EnvironmentMetadata: v1alpha1.EnvironmentMetadata{ObjectMeta: metav1.ObjectMeta{Name: "prod"}}}
+	release.Environment = env
+
+	return &catalog.Catalog{
+		Environments: []*v1alpha1.Environment{env},
+		Releases: cross.ReleaseList{
+			Environments: []*v1alpha1.Environment{env},
+			Items:        []*cross.Release{{Name: "api", Releases: []*v1alpha1.Release{release}}},
+		},
+	}
+}
+
+func TestRollback(t *testing.T) {
+	revisions := []history.Revision{
+		newRevision("c3", "1.3.0", "2.0.0"),
+		newRevision("c2", "1.2.0", "1.9.0"),
+		newRevision("c1", "1.1.0", "1.9.0"),
+		newRevision("c0", "1.0.0", ""),
+	}
+
+	cases := []struct {
+		name            string
+		toVersion       string
+		expectedYAML    string
+		expectedTitle   string
+		expectedBody    string
+		expectedCommit  string
+		expectedErr     string
+		expectedCreated bool
+	}{
+		{
+			name: "previous version",
+			expectedYAML: `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.2.0
+  chart:
+    ref: generic
+    version: 1.9.0
+  values:
+    replicas: !lock 3
+    env:
+      HOST: !local api.prod.acme.com
+      FEATURE: enabled
+`,
+			expectedTitle:   "Rollback api 1.3.0 -> 1.2.0 (prod)",
+			expectedCommit:  "c2",
+			expectedCreated: true,
+		},
+		{
+			name:            "specific version",
+			toVersion:       "1.1.0",
+			expectedTitle:   "Rollback api 1.3.0 -> 1.1.0 (prod)",
+			expectedCommit:  "c1",
+			expectedCreated: true,
+		},
+		{
+			name:      "version without chart version",
+			toVersion: "1.0.0",
+			expectedYAML: `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.0.0
+  chart:
+    ref: generic
+  values:
+    replicas: !lock 3
+    env:
+      HOST: !local api.prod.acme.com
+      FEATURE: enabled
+`,
+			expectedTitle:   "Rollback api 1.3.0 -> 1.0.0 (prod)",
+			expectedBody:    "and its chart from version 2.0.0 to the default version of the environment",
+			expectedCommit:  "c0",
+			expectedCreated: true,
+		},
+		{
+			name:        "unknown version",
+			toVersion:   "0.9.0",
+			expectedErr: "version 0.9.0 not found in history of release api",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var written *yml.File
+
+			historyProvider := &history.ProviderMock{
+				GetFileRevisionsFunc: func(file string) ([]history.Revision, error) {
+					return revisions, nil
+				},
+			}
+			gitProvider := &promote.GitProviderMock{}
+			prProvider := &pr.PullRequestProviderMock{
+				CreateFunc: func(params pr.CreateParams) (string, error) {
+					return "https://github.com/acme/catalog/pull/1", nil
+				},
+			}
+			writer := &yml.WriterMock{
+				WriteFileFunc: func(file *yml.File) error {
+					written = file
+					return nil
+				},
+			}
+
+			rollback := Rollback{
+				HistoryProvider:     historyProvider,
+				GitProvider:         gitProvider,
+				PullRequestProvider: prProvider,
+				YamlWriter:          writer,
+				Out:                 io.Discard,
+			}
+
+			prURL, err := rollback.Rollback(Opts{
+				Catalog:     newCatalog(t),
+				Environment: "prod",
+				Release:     "api",
+				ToVersion:   tc.toVersion,
+			})
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				require.Empty(t, writer.WriteFileCalls())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "https://github.com/acme/catalog/pull/1", prURL)
+
+			if tc.expectedYAML != "" {
+				actual, err := written.Yaml()
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedYAML, string(actual))
+			}
+
+			require.Len(t, gitProvider.CreateAndPushBranchWithFilesCalls(), 1)
+			require.Equal(t, []string{"/catalog/environments/prod/releases/api.yaml"}, gitProvider.CreateAndPushBranchWithFilesCalls()[0].Files)
+
+			require.Len(t, prProvider.CreateCalls(), 1)
+			params := prProvider.CreateCalls()[0].CreateParams
+			require.Equal(t, tc.expectedTitle, params.Title)
+			require.Contains(t, params.Body, "as found in catalog commit "+tc.expectedCommit)
+			if tc.expectedBody != "" {
+				require.Contains(t, params.Body, tc.expectedBody)
+			}
+			require.Equal(t, []string{"environment:prod", "release:api", "rollback"}, params.Labels)
+
+			require.Len(t, gitProvider.CheckoutMasterBranchCalls(), 1)
+		})
+	}
+}
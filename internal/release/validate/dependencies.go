@@ -0,0 +1,51 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+// ValidateDependencies checks that all releases the given release depends on exist in its environment and that it is
+// not part of a dependency cycle. Dependencies must have been resolved beforehand, as is done when loading the catalog.
+func ValidateDependencies(release *v1alpha1.Release) error {
+	var errs []error
+	for _, name := range release.Spec.DependsOn {
+		if !slices.ContainsFunc(release.Dependencies, func(dependency *v1alpha1.Release) bool { return dependency.Name == name }) {
+			errs = append(errs, fmt.Errorf("depends on unknown release %q", name))
+		}
+	}
+
+	visited := map[*v1alpha1.Release]bool{}
+	if cycle := findDependencyCycle(release, []*v1alpha1.Release{release}, visited); cycle != nil {
+		names := make([]string, len(cycle))
+		for i, rel := range cycle {
+			names[i] = rel.Name
+		}
+		errs = append(errs, fmt.Errorf("dependency cycle: %s", strings.Join(names, " -> ")))
+	}
+
+	return errors.Join(errs...)
+}
+
+// findDependencyCycle walks the dependencies of the last release of given path depth-first and returns the path
+// leading back to the start release, if any. Cycles not involving the start release are ignored, as they get reported
+// when validating their own releases.
+func findDependencyCycle(start *v1alpha1.Release, path []*v1alpha1.Release, visited map[*v1alpha1.Release]bool) []*v1alpha1.Release {
+	for _, dependency := range path[len(path)-1].Dependencies {
+		if dependency == start {
+			return append(slices.Clone(path), dependency)
+		}
+		if visited[dependency] {
+			continue
+		}
+		visited[dependency] = true
+		if cycle := findDependencyCycle(start, append(path, dependency), visited); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
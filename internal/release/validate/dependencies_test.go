@@ -0,0 +1,43 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	newRelease := func(name string, dependsOn ...string) *v1alpha1.Release {
+		return &v1alpha1.Release{
+			ReleaseMetadata: v1alpha1.ReleaseMetadata{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			Spec:            v1alpha1.ReleaseSpec{DependsOn: dependsOn},
+		}
+	}
+
+	api := newRelease("api", "migrations", "cache")
+	migrations := newRelease("migrations")
+	api.Dependencies = []*v1alpha1.Release{migrations}
+
+	require.EqualError(t, ValidateDependencies(api), `depends on unknown release "cache"`)
+	require.NoError(t, ValidateDependencies(migrations))
+
+	a, b, c := newRelease("a", "b"), newRelease("b", "c"), newRelease("c", "a")
+	a.Dependencies = []*v1alpha1.Release{b}
+	b.Dependencies = []*v1alpha1.Release{c}
+	c.Dependencies = []*v1alpha1.Release{a}
+
+	require.EqualError(t, ValidateDependencies(a), "dependency cycle: a -> b -> c -> a")
+	require.EqualError(t, ValidateDependencies(c), "dependency cycle: c -> a -> b -> c")
+
+	self := newRelease("self", "self")
+	self.Dependencies = []*v1alpha1.Release{self}
+	require.EqualError(t, ValidateDependencies(self), "dependency cycle: self -> self")
+
+	// Releases depending on a cycle are not themselves part of it
+	d := newRelease("d", "a")
+	d.Dependencies = []*v1alpha1.Release{a}
+	require.NoError(t, ValidateDependencies(d))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/davidmdm/x/xerr"
 	"golang.org/x/mod/semver"
@@ -21,35 +23,77 @@ type ValidateParams struct {
 	NoRender    bool
 	NoValueTags bool
 	UseRawYaml  bool
+
+	// Concurrency is the maximum number of releases validated at once, defaulting to one.
+	Concurrency int
 }
 
 func Validate(ctx context.Context, params ValidateParams) error {
-	var errs []error
-	for _, release := range params.Releases {
-		chart, err := func() (*helm.ChartFS, error) {
-			if params.NoRender {
-				return nil, nil
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		chartErrs = make([]error, len(params.Releases))
+		errs      = make([]error, len(params.Releases))
+		indexes   = make(chan int)
+		wg        sync.WaitGroup
+	)
+
+	for range min(max(params.Concurrency, 1), len(params.Releases)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					continue
+				}
+				if chartErrs[i], errs[i] = validate(ctx, params, params.Releases[i]); chartErrs[i] != nil {
+					// Failing to get a chart aborts the whole validation, so there is no point in validating the rest
+					cancel()
+				}
 			}
-			return params.ChartCache.GetReleaseChartFS(ctx, release)
 		}()
+	}
+
+	for i := range params.Releases {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Chart errors are reported for the first release in the given order, regardless of scheduling
+	for _, err := range chartErrs {
 		if err != nil {
 			return fmt.Errorf("getting release chart: %w", err)
 		}
+	}
 
-		validateParams := ValidateReleaseParams{
-			Chart:                 chart,
-			Release:               release,
-			Helm:                  params.Helm,
-			NoTagsOnMappingValues: params.NoValueTags,
-			UseRawYaml:            params.UseRawYaml,
-		}
+	errs = slices.DeleteFunc(errs, func(err error) bool { return err == nil })
 
-		if err := ValidateRelease(ctx, validateParams); err != nil {
-			errs = append(errs, fmt.Errorf("%s/%s: %w", release.Name, release.Environment.Name, err))
+	return xerr.MultiErrOrderedFrom("validating releases", errs...)
+}
+
+// validate validates a single release, returning separately the error of getting its chart, if any.
+func validate(ctx context.Context, params ValidateParams, release *v1alpha1.Release) (chartErr, err error) {
+	var chart *helm.ChartFS
+	if !params.NoRender {
+		if chart, err = params.ChartCache.GetReleaseChartFS(ctx, release); err != nil {
+			return err, nil
 		}
 	}
 
-	return xerr.MultiErrOrderedFrom("validating releases", errs...)
+	validateParams := ValidateReleaseParams{
+		Chart:                 chart,
+		Release:               release,
+		Helm:                  params.Helm,
+		NoTagsOnMappingValues: params.NoValueTags,
+		UseRawYaml:            params.UseRawYaml,
+	}
+
+	if err := ValidateRelease(ctx, validateParams); err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", release.Name, release.Environment.Name, err)
+	}
+	return nil, nil
 }
 
 type ValidateReleaseParams struct {
@@ -68,6 +112,10 @@ func ValidateRelease(ctx context.Context, params ValidateReleaseParams) error {
 		}
 	}
 
+	if err := ValidateDependencies(params.Release); err != nil {
+		return err
+	}
+
 	if yml.HasLockedTodos(params.Release.File.Tree) {
 		return errors.New("contains locked TODO")
 	}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 
 	"github.com/davidmdm/x/xfs"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/nestoca/joy/api/v1alpha1"
 	"github.com/nestoca/joy/internal/yml"
@@ -40,7 +43,7 @@ func TestValidateRelease(t *testing.T) {
 				Environment: &allowPullRequest,
 			},
 			ChartFS: &xfs.FSMock{
-				ReadFileFunc: func(string) ([]byte, error) { return []byte(`#values: { hello: string }`), nil },
+				ReadFileFunc: cueSchema(`#values: { hello: string }`),
 				DirNameFunc:  func() string { return "." },
 			},
 			ExpectedErr: "",
@@ -52,10 +55,10 @@ func TestValidateRelease(t *testing.T) {
 				Environment: &allowPullRequest,
 			},
 			ChartFS: &xfs.FSMock{
-				ReadFileFunc: func(string) ([]byte, error) { return []byte(`#values: { hello: string }`), nil },
+				ReadFileFunc: cueSchema(`#values: { hello: string }`),
 				DirNameFunc:  func() string { return "." },
 			},
-			ExpectedErr: "hydrating values: unifying with chart schema: validating values: #values.hello: conflicting values string and true (mismatched types string and bool)",
+			ExpectedErr: "hydrating values: unifying with chart schema: validating values: line 1: #values.hello: conflicting values string and true (mismatched types string and bool)",
 		},
 		{
 			Name: "values missing from spec",
@@ -64,9 +67,9 @@ func TestValidateRelease(t *testing.T) {
 				Environment: &allowPullRequest,
 			},
 			ChartFS: &xfs.FSMock{
-				ReadFileFunc: func(string) ([]byte, error) { return []byte(`#values: { hello: string }`), nil },
+				ReadFileFunc: cueSchema(`#values: { hello: string }`),
 			},
-			ExpectedErr: "hydrating values: unifying with chart schema: validating values: #values.hello: incomplete value string",
+			ExpectedErr: "hydrating values: unifying with chart schema: validating values: line 1: #values.hello: incomplete value string",
 		},
 		{
 			Name: "multiple errors",
@@ -75,12 +78,12 @@ func TestValidateRelease(t *testing.T) {
 				Environment: &allowPullRequest,
 			},
 			ChartFS: &xfs.FSMock{
-				ReadFileFunc: func(string) ([]byte, error) { return []byte(`#values: { one: 1, two: 2 }`), nil },
+				ReadFileFunc: cueSchema(`#values: { one: 1, two: 2 }`),
 			},
 			ExpectedErr: "" +
 				"hydrating values: unifying with chart schema: validating values:\n" +
-				"  - #values.one: conflicting values 1 and \"one\" (mismatched types int and string)\n" +
-				"  - #values.two: conflicting values 2 and \"two\" (mismatched types int and string)",
+				"  - line 1: #values.one: conflicting values 1 and \"one\" (mismatched types int and string)\n" +
+				"  - line 1: #values.two: conflicting values 2 and \"two\" (mismatched types int and string)",
 		},
 		{
 			Name: "render fails",
@@ -89,7 +92,7 @@ func TestValidateRelease(t *testing.T) {
 				Environment: &allowPullRequest,
 			},
 			ChartFS: &xfs.FSMock{
-				ReadFileFunc: func(string) ([]byte, error) { return []byte(`#values: { hello: string }`), nil },
+				ReadFileFunc: cueSchema(`#values: { hello: string }`),
 				DirNameFunc:  func() string { return "" },
 			},
 
@@ -136,7 +139,7 @@ func TestValidateRelease(t *testing.T) {
 				Project:     &v1alpha1.Project{Spec: v1alpha1.ProjectSpec{}},
 			},
 			ChartFS: &xfs.FSMock{
-				ReadFileFunc: func(string) ([]byte, error) { return []byte(`#values: { hello: string }`), nil },
+				ReadFileFunc: cueSchema(`#values: { hello: string }`),
 				DirNameFunc:  func() string { return "." },
 			},
 			ExpectedErr: "",
@@ -159,7 +162,7 @@ func TestValidateRelease(t *testing.T) {
 				Project:     &v1alpha1.Project{Spec: v1alpha1.ProjectSpec{SkipPreReleaseCheck: true}},
 			},
 			ChartFS: &xfs.FSMock{
-				ReadFileFunc: func(string) ([]byte, error) { return []byte(`#values: { hello: string }`), nil },
+				ReadFileFunc: cueSchema(`#values: { hello: string }`),
 				DirNameFunc:  func() string { return "." },
 			},
 			ExpectedErr: "",
@@ -183,7 +186,7 @@ func TestValidateRelease(t *testing.T) {
 				Project:     &v1alpha1.Project{Spec: v1alpha1.ProjectSpec{}},
 			},
 			ChartFS: &xfs.FSMock{
-				ReadFileFunc: func(string) ([]byte, error) { return []byte(`#values: { hello: "world" }`), nil },
+				ReadFileFunc: cueSchema(`#values: { hello: "world" }`),
 				DirNameFunc:  func() string { return "." },
 			},
 			ExpectedErr: "",
@@ -196,10 +199,10 @@ func TestValidateRelease(t *testing.T) {
 				Project:     &v1alpha1.Project{Spec: v1alpha1.ProjectSpec{}},
 			},
 			ChartFS: &xfs.FSMock{
-				ReadFileFunc: func(string) ([]byte, error) { return []byte(`#values: { hello: "world" }`), nil },
+				ReadFileFunc: cueSchema(`#values: { hello: "world" }`),
 				DirNameFunc:  func() string { return "." },
 			},
-			ExpectedErr: `hydrating values: unifying with chart schema: validating values: #values.hello: conflicting values "narnia" and "world"`,
+			ExpectedErr: `hydrating values: unifying with chart schema: validating values: line 1: #values.hello: conflicting values "narnia" and "world"`,
 		},
 		{
 			Name: "contains locked todos",
@@ -244,7 +247,7 @@ func TestValidateRelease(t *testing.T) {
 				return
 			}
 
-			require.Len(t, tc.ChartFS.ReadFileCalls(), 1)
+			require.NotEmpty(t, tc.ChartFS.ReadFileCalls())
 			require.Equal(t, "values.cue", tc.ChartFS.ReadFileCalls()[0].Name)
 
 			if tc.ExpectedErr == "" {
@@ -256,3 +259,64 @@ func TestValidateRelease(t *testing.T) {
 		})
 	}
 }
+
+// cueSchema returns a ReadFileFunc for a chart with given values.cue file only.
+func cueSchema(schema string) func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		if name != "values.cue" {
+			return nil, os.ErrNotExist
+		}
+		return []byte(schema), nil
+	}
+}
+
+func TestValidate(t *testing.T) {
+	env := func(name string) *v1alpha1.Environment {
+		return &v1alpha1.Environment{
+			EnvironmentMetadata: v1alpha1.EnvironmentMetadata{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			Spec:                v1alpha1.EnvironmentSpec{Promotion: v1alpha1.Promotion{FromPullRequests: true}},
+		}
+	}
+
+	var releases []*v1alpha1.Release
+	for _, envName := range []string{"staging", "prod"} {
+		for _, name := range []string{"worker", "api", "broken"} {
+			file, err := yml.NewFile("./"+name+".yaml", []byte("spec: { chart: { repoUrl: acme.com/charts, name: app, version: 1.0.0 } }"))
+			require.NoError(t, err)
+
+			release := &v1alpha1.Release{File: file, Environment: env(envName)}
+			require.NoError(t, file.Tree.Decode(release))
+			release.Name = name
+
+			releases = append(releases, release)
+		}
+	}
+
+	var pulls atomic.Int32
+	mock := &helm.PullRendererMock{
+		PullFunc: func(ctx context.Context, opts helm.PullOptions) (helm.PullResult, error) {
+			pulls.Add(1)
+			return helm.PullResult{}, os.MkdirAll(filepath.Join(opts.OutputDir, opts.Chart.Name), 0o755)
+		},
+		RenderFunc: func(ctx context.Context, opts helm.RenderOpts) (string, error) {
+			if opts.ReleaseName == "broken" {
+				return "", errors.New("failed to render")
+			}
+			return "", nil
+		},
+	}
+
+	err := Validate(context.Background(), ValidateParams{
+		Releases:    releases,
+		Helm:        mock,
+		ChartCache:  helm.ChartCache{Root: t.TempDir(), Puller: mock},
+		Concurrency: 4,
+	})
+
+	require.EqualError(t, err, "validating releases:\n"+
+		"  - broken/prod: failed to render\n"+
+		"  - broken/staging: failed to render")
+
+	require.EqualValues(t, 1, pulls.Load(), "chart should be pulled once for all releases")
+	require.Len(t, mock.RenderCalls(), len(releases))
+}
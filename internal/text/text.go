@@ -56,3 +56,16 @@ func indentLines(lines []string, indent string) []string {
 	}
 	return result
 }
+
+// Truncate shortens given value to at most width runes, ending it with an ellipsis when there is room for one. A width
+// of 0 or less leaves the value untouched.
+func Truncate(value string, width int) string {
+	runes := []rune(value)
+	if width <= 0 || len(runes) <= width {
+		return value
+	}
+	if width < 4 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
@@ -0,0 +1,27 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		value    string
+		width    int
+		expected string
+	}{
+		{value: "Promote api", width: 0, expected: "Promote api"},
+		{value: "Promote api", width: 11, expected: "Promote api"},
+		{value: "Promote api", width: 10, expected: "Promote..."},
+		{value: "Promote api", width: 4, expected: "P..."},
+		{value: "Promote api", width: 2, expected: "Pr"},
+		{value: "Promote api", width: 1, expected: "P"},
+		{value: "Déployer l’api", width: 8, expected: "Déplo..."},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.expected, Truncate(tc.value, tc.width), "%q truncated to %d", tc.value, tc.width)
+	}
+}
@@ -102,6 +102,38 @@ func setOrAddNodeValue(node *yaml.Node, pathSegments []string, value string) err
 	return nil
 }
 
+// RemoveNode removes the key and value at the provided path, along with any mapping left empty by the removal, and
+// reports whether the path was found.
+func RemoveNode(node *yaml.Node, path string) bool {
+	// If node is a DocumentNode, we need to retrieve the MappingNode from its Content and traverse it.
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
+	}
+	return removeNode(node, SplitIntoPathSegments(path))
+}
+
+func removeNode(node *yaml.Node, pathSegments []string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != pathSegments[0] {
+			continue
+		}
+
+		valueNode := node.Content[i+1]
+		if len(pathSegments) > 1 {
+			if valueNode.Kind != yaml.MappingNode || !removeNode(valueNode, pathSegments[1:]) {
+				return false
+			}
+			if len(valueNode.Content) > 0 {
+				return true
+			}
+		}
+
+		node.Content = append(node.Content[:i], node.Content[i+2:]...)
+		return true
+	}
+	return false
+}
+
 func findKeypair(node *yaml.Node, pathSegments []string) (*KeyValuePair, error) {
 	// Condition is i+1 < len(node.Content) as there always need to be at least 2 entries left in the node.Content for
 	// traversal to work, because each key and its associated value are stored in two consecutive nodes in the slice.
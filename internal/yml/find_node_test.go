@@ -3,6 +3,8 @@ package yml
 import (
 	"testing"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -52,3 +54,16 @@ func TestSplitIntoPathSegments(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveNode(t *testing.T) {
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("spec:\n  version: 1.0.0\n  chart:\n    version: 2.0.0\n"), &node))
+
+	require.False(t, RemoveNode(&node, "spec.chart.ref"))
+	require.False(t, RemoveNode(&node, "spec.version.major"))
+	require.True(t, RemoveNode(&node, "spec.chart.version"))
+
+	actual, err := yaml.Marshal(&node)
+	require.NoError(t, err)
+	require.Equal(t, "spec:\n    version: 1.0.0\n", string(actual), "empty chart mapping is removed too")
+}
@@ -0,0 +1,202 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+// fileCache is an on-disk index of the catalog files, keyed by path, remembering which files are not joy resources
+// (so they need not be parsed again) and which resources passed schema validation (so they need not be validated
+// again), for as long as their content is unchanged.
+type fileCache struct {
+	path string
+
+	mu      sync.Mutex
+	dirty   bool
+	Schema  string                    `json:"schema"`
+	Entries map[string]fileCacheEntry `json:"entries"`
+}
+
+type fileCacheEntry struct {
+	ModTime time.Time `json:"modTime"`
+	Size    int64     `json:"size"`
+	Hash    string    `json:"hash"`
+
+	// Resource is whether the file is a joy resource (environment, project, release or release group).
+	Resource bool `json:"resource"`
+
+	// Valid is whether the resource passed schema validation.
+	Valid bool `json:"valid,omitempty"`
+}
+
+// openFileCache opens the cache of given catalog directory within the joy cache directory. A missing, corrupted or
+// outdated cache is simply discarded.
+func openFileCache(cacheDir, catalogDir string) *fileCache {
+	key := sha256.Sum256([]byte(catalogDir))
+
+	cache := &fileCache{
+		path:    filepath.Join(cacheDir, "catalog", hex.EncodeToString(key[:8])+".json"),
+		Schema:  v1alpha1.SchemaDigest(),
+		Entries: map[string]fileCacheEntry{},
+	}
+
+	data, err := os.ReadFile(cache.path)
+	if err != nil {
+		return cache
+	}
+
+	var stored fileCache
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Schema != cache.Schema {
+		return cache
+	}
+
+	if stored.Entries != nil {
+		cache.Entries = stored.Entries
+	}
+	return cache
+}
+
+// lookup returns the cache entry of given file, if its modification time and size are unchanged.
+func (cache *fileCache) lookup(path string, info os.FileInfo) (fileCacheEntry, bool) {
+	if cache == nil {
+		return fileCacheEntry{}, false
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.Entries[path]
+	if !ok || !entry.ModTime.Equal(info.ModTime()) || entry.Size != info.Size() {
+		return fileCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// update records the current state of given file, retaining whether it passed validation if its content is unchanged.
+func (cache *fileCache) update(path string, info os.FileInfo, hash string, resource bool) {
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	previous := cache.Entries[path]
+	entry := fileCacheEntry{
+		ModTime:  info.ModTime(),
+		Size:     info.Size(),
+		Hash:     hash,
+		Resource: resource,
+		Valid:    resource && previous.Valid && previous.Hash == hash,
+	}
+	if entry != previous {
+		cache.Entries[path] = entry
+		cache.dirty = true
+	}
+}
+
+// isValid returns whether given file is known to have passed schema validation.
+func (cache *fileCache) isValid(path string) bool {
+	if cache == nil {
+		return false
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.Entries[path].Valid
+}
+
+// setValid records that given file passed schema validation.
+func (cache *fileCache) setValid(path string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if entry, ok := cache.Entries[path]; ok && !entry.Valid {
+		entry.Valid = true
+		cache.Entries[path] = entry
+		cache.dirty = true
+	}
+}
+
+// prune removes entries of files that no longer exist in the catalog.
+func (cache *fileCache) prune(paths []string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	existing := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		existing[path] = true
+	}
+	for path := range cache.Entries {
+		if !existing[path] {
+			delete(cache.Entries, path)
+			cache.dirty = true
+		}
+	}
+}
+
+// save writes the cache to disk if it changed. It is written to a temporary file first, such that concurrent joy
+// processes never observe a partially written cache.
+func (cache *fileCache) save() error {
+	if cache == nil {
+		return nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if !cache.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("marshalling catalog cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cache.path), 0o755); err != nil {
+		return fmt.Errorf("creating catalog cache directory: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(cache.path), filepath.Base(cache.path)+".*")
+	if err != nil {
+		return fmt.Errorf("creating catalog cache file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		return errors.Join(fmt.Errorf("writing catalog cache: %w", err), temp.Close())
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("closing catalog cache: %w", err)
+	}
+	if err := os.Rename(temp.Name(), cache.path); err != nil {
+		return fmt.Errorf("replacing catalog cache: %w", err)
+	}
+
+	cache.dirty = false
+	return nil
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
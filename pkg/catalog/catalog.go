@@ -11,11 +11,10 @@ import (
 	"strings"
 
 	"github.com/davidmdm/x/xerr"
-	"gopkg.in/godo.v2/glob"
 
 	"github.com/nestoca/joy/api/v1alpha1"
-	"github.com/nestoca/joy/internal/ignore"
 	"github.com/nestoca/joy/internal/observability"
+	"github.com/nestoca/joy/internal/references"
 	"github.com/nestoca/joy/internal/release/cross"
 	"github.com/nestoca/joy/internal/release/filtering"
 	"github.com/nestoca/joy/internal/yml"
@@ -36,15 +35,23 @@ type Catalog struct {
 	Releases     ReleaseList
 	Projects     []*v1alpha1.Project
 	Files        []*yml.File
+
+	// ReleaseGroups are named sets of releases that are typically listed, selected and promoted together.
+	ReleaseGroups []*v1alpha1.ReleaseGroup
 }
 
-func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, error) {
+func Load(ctx context.Context, dir string, validChartRefs []string, options ...LoadOption) (c *Catalog, err error) {
 	_, span := observability.StartTrace(ctx, "load_catalog")
 	defer span.End()
 
+	var opts loadOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
 	// Get absolute and clean path of directory, so we can determine whether a release belongs to an environment
 	// by simply comparing the beginning of their paths.
-	dir, err := filepath.Abs(dir)
+	dir, err = filepath.Abs(dir)
 	if err != nil {
 		return nil, fmt.Errorf("getting absolute path of %s: %w", dir, err)
 	}
@@ -57,56 +64,59 @@ func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, e
 		}
 	}
 
-	// Find all files matching the glob expression
-	globExpr := filepath.Join(dir, "**/*.yaml")
-	fileAssets, _, err := glob.Glob([]string{globExpr})
-	if err != nil {
-		return nil, fmt.Errorf("matching files with glob expression %s: %w", globExpr, err)
-	}
-
-	// Load .joyignore if it exists
-	ignoreMatcher, err := ignore.NewMatcher(dir)
-	if err != nil && !os.IsNotExist(err) {
-		return nil, fmt.Errorf("reading .joyignore: %w", err)
-	}
+	c = &Catalog{Dir: dir}
+	var cache *fileCache
 
-	// Load all matching files
-	c := &Catalog{Dir: dir}
-	for _, fileAsset := range fileAssets {
-		// Skip files living inside a hidden directory (e.g. .git, .claude).
-		// Nested git worktrees and checkouts are commonly placed under such
-		// folders (for example .claude/worktrees/<name>) and each contains its
-		// own full copy of the catalog. Without this, every environment,
-		// release and project would be loaded once per checkout, duplicating
-		// the whole catalog N times.
-		if hidden, err := hasHiddenDirSegment(dir, fileAsset.Path); err != nil {
+	if opts.revision != "" {
+		c.Files, err = loadFilesAtRevision(dir, opts.revision)
+		if err != nil {
+			return nil, fmt.Errorf("loading files at revision %s: %w", opts.revision, err)
+		}
+	} else {
+		paths, err := listFiles(dir)
+		if err != nil {
 			return nil, err
-		} else if hidden {
-			continue
 		}
 
-		if ignoreMatcher != nil && ignoreMatcher.Match(fileAsset.Path) {
-			continue
+		if opts.cacheDir != "" {
+			cache = openFileCache(opts.cacheDir, dir)
+			cache.prune(paths)
+			defer func() {
+				// Failing to save the cache only makes the next load slower, so it must not fail this one
+				_ = cache.save()
+			}()
 		}
 
-		file, err := yml.LoadFile(fileAsset.Path)
+		// Load all matching files
+		c.Files, err = loadFiles(paths, cache)
 		if err != nil {
-			return nil, fmt.Errorf("loading yaml file %s: %w", fileAsset.Path, err)
-		}
-		// Only keep Joy CRDs
-		if isValid(file) {
-			c.Files = append(c.Files, file)
+			return nil, err
 		}
 	}
 
-	c.Environments, err = c.loadEnvironments(nil, true)
+	c.Files = opts.filterFiles(c.Files)
+
+	c.Environments, err = c.loadEnvironments(opts.environments, true)
 	if err != nil {
 		return nil, fmt.Errorf("loading environments: %w", err)
 	}
 
+	if len(opts.environments) > 0 {
+		c.Files = filterReleaseFiles(c.Files, c.Environments)
+	}
+
+	var schemaErrs map[*yml.File]error
+	if !opts.skipValidation {
+		schemaErrs = validateSchemas(c.Files, cache)
+	}
+
 	var errs []error
 	for _, env := range c.Environments {
-		if err := env.Validate(validChartRefs); err != nil {
+		var chartRefsErr error
+		if !opts.skipValidation {
+			chartRefsErr = env.ValidateChartRefs(validChartRefs)
+		}
+		if err := xerr.MultiErrOrderedFrom("", schemaErrs[env.File], chartRefsErr); err != nil {
 			errs = append(errs, fmt.Errorf("%s: %w", env.Name, err))
 		}
 	}
@@ -121,7 +131,7 @@ func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, e
 	}
 
 	for _, project := range c.Projects {
-		if err := project.Validate(); err != nil {
+		if err := schemaErrs[project.File]; err != nil {
 			errs = append(errs, fmt.Errorf("%s: %w", project.Name, err))
 		}
 	}
@@ -132,8 +142,10 @@ func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, e
 
 	allReleaseFiles := c.GetFilesByKind(v1alpha1.ReleaseKind)
 
-	if err := validateTagsForFiles(allReleaseFiles); err != nil {
-		return nil, fmt.Errorf("release files with invalid tags: %w", err)
+	if !opts.skipValidation {
+		if err := validateTagsForFiles(allReleaseFiles); err != nil {
+			return nil, fmt.Errorf("release files with invalid tags: %w", err)
+		}
 	}
 
 	c.Releases, err = cross.LoadReleaseList(allReleaseFiles, c.Environments, c.Projects, nil)
@@ -150,10 +162,13 @@ func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, e
 			if release == nil {
 				continue
 			}
+			if opts.skipValidation {
+				continue
+			}
 			if err := release.Spec.Chart.Validate(validChartRefs); err != nil {
 				errs = append(errs, fmt.Errorf("%s/%s: invalid chart: %w", release.Name, release.Environment.Name, err))
 			}
-			if err := release.Validate(); err != nil {
+			if err := schemaErrs[release.File]; err != nil {
 				errs = append(errs, fmt.Errorf("%s/%s: validation: %w", release.Name, release.Environment.Name, err))
 			}
 		}
@@ -162,6 +177,38 @@ func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, e
 		return nil, err
 	}
 
+	c.ReleaseGroups, err = c.loadReleaseGroups()
+	if err != nil {
+		return nil, fmt.Errorf("loading release groups: %w", err)
+	}
+
+	releaseNames := c.GetReleaseNames()
+	for _, group := range c.ReleaseGroups {
+		if opts.skipValidation {
+			break
+		}
+		if err := schemaErrs[group.File]; err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", group.Name, err))
+			continue
+		}
+		if _, err := filtering.NewReleaseGroupFilter(group); err != nil {
+			errs = append(errs, err)
+		}
+		// Members cannot be verified when only some of the releases are loaded
+		if opts.isPartial() {
+			continue
+		}
+		for _, name := range group.Spec.Releases {
+			if !slices.Contains(releaseNames, name) {
+				errs = append(errs, references.NewMissingError("ReleaseGroup", group.Name, "Release", name))
+			}
+		}
+	}
+
+	if err := xerr.MultiErrOrderedFrom("validating release groups", errs...); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
@@ -186,6 +233,108 @@ func (c *Catalog) WithReleaseFilter(filter filtering.Filter) {
 	}
 }
 
+// WithCrossReleaseFilter restricts releases to those whose release in given environment matches the filter, or in any
+// environment if none is given. Unlike WithReleaseFilter, releases are kept or dropped as a whole across environments,
+// such that the releases of other environments, such as the target of a promotion, are kept even if their labels
+// differ.
+func (c *Catalog) WithCrossReleaseFilter(filter filtering.Filter, env string) {
+	if filter == nil {
+		return
+	}
+
+	envIndex := -1
+	if env != "" {
+		envIndex = c.Releases.GetEnvironmentIndexByName(env)
+	}
+
+	releases := c.Releases.Items
+	c.Releases.Items = []*cross.Release{}
+
+	for _, cross := range releases {
+		matches := slices.ContainsFunc(cross.Releases, func(rel *v1alpha1.Release) bool { return rel != nil && filter.Match(rel) })
+		if envIndex >= 0 {
+			rel := cross.Releases[envIndex]
+			matches = rel != nil && filter.Match(rel)
+		}
+		if matches {
+			c.Releases.Items = append(c.Releases.Items, cross)
+		}
+	}
+}
+
+// WithReleaseGroup restricts releases to the members of the release group with given name.
+func (c *Catalog) WithReleaseGroup(name string) error {
+	filter, err := c.ReleaseGroupFilter(name)
+	if err != nil {
+		return err
+	}
+	c.WithReleaseFilter(filter)
+	return nil
+}
+
+// ReleaseGroupFilter returns the filter matching the members of the release group with given name.
+func (c *Catalog) ReleaseGroupFilter(name string) (filtering.Filter, error) {
+	group, err := v1alpha1.GetReleaseGroupByName(c.ReleaseGroups, name)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := filtering.NewReleaseGroupFilter(group)
+	if err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// WithReleasePatterns restricts releases to those whose name matches any of given patterns, which can be exact names,
+// globs or /regular expressions/, returning an error if any pattern matches no release.
+func (c *Catalog) WithReleasePatterns(patterns []string) error {
+	filter, err := filtering.NewNamePatternsFilter(patterns)
+	if err != nil {
+		return err
+	}
+	if _, err := filter.MatchingNames(c.GetReleaseNames()); err != nil {
+		return err
+	}
+	c.WithReleaseFilter(filter)
+	return nil
+}
+
+// ExpandReleasePatterns replaces the globs and /regular expressions/ among given patterns with the names of matching
+// releases, returning an error if any of them matches no release. Exact names are kept as is, leaving it up to the
+// caller to report unknown releases in context.
+func (c *Catalog) ExpandReleasePatterns(patterns []string) ([]string, error) {
+	var (
+		names    []string
+		expanded []filtering.NamePattern
+	)
+	for _, pattern := range patterns {
+		namePattern, err := filtering.ParseNamePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if namePattern.IsExact() {
+			names = append(names, pattern)
+			continue
+		}
+		expanded = append(expanded, namePattern)
+	}
+
+	if len(expanded) == 0 {
+		return names, nil
+	}
+
+	matching, err := (&filtering.NamePatternFilter{Patterns: expanded}).MatchingNames(c.GetReleaseNames())
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range matching {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
 func (c *Catalog) WithReleases(names []string) {
 	if len(names) == 0 {
 		return
@@ -246,6 +395,9 @@ func (c *Catalog) ResolveRefs() error {
 		if len(c.Environments) > 0 {
 			c.Releases.ResolveEnvRefs(c.Environments)
 		}
+
+		// Resolve references from releases to the releases they depend on
+		c.Releases.ResolveDependencyRefs()
 	}
 	return errors.Join(errs...)
 }
@@ -275,7 +427,8 @@ func hasHiddenDirSegment(root, path string) (bool, error) {
 func isValid(file *yml.File) bool {
 	return v1alpha1.IsValidEnvironment(file.ApiVersion, file.Kind) ||
 		v1alpha1.IsValidRelease(file.ApiVersion, file.Kind) ||
-		v1alpha1.IsValidProject(file.ApiVersion, file.Kind)
+		v1alpha1.IsValidProject(file.ApiVersion, file.Kind) ||
+		v1alpha1.IsValidReleaseGroup(file.ApiVersion, file.Kind)
 }
 
 // GetFilesByKind returns all files of the given kind.
@@ -380,6 +533,26 @@ func (c *Catalog) loadProjects() ([]*v1alpha1.Project, error) {
 	return projects, nil
 }
 
+func (c *Catalog) loadReleaseGroups() ([]*v1alpha1.ReleaseGroup, error) {
+	files := c.GetFilesByKind(v1alpha1.ReleaseGroupKind)
+
+	var groups []*v1alpha1.ReleaseGroup
+	for _, file := range files {
+		group, err := v1alpha1.NewReleaseGroup(file)
+		if err != nil {
+			return nil, fmt.Errorf("loading release group from %s: %w", file.Path, err)
+		}
+		groups = append(groups, group)
+	}
+
+	// Sort release groups by name
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups, nil
+}
+
 func (c *Catalog) GetReleaseNames() []string {
 	result := []string{}
 	for _, cross := range c.Releases.Items {
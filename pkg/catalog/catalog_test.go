@@ -2,9 +2,13 @@ package catalog
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -34,6 +38,11 @@ func TestCatalogLoadE2E(t *testing.T) {
 			Folder: "invalid-crd-schema",
 			Error:  "unmarshalling project: yaml: unmarshal errors:\n  line 6: field unknown-key not found in type v1alpha1.ProjectSpe",
 		},
+		{
+			Name:   "release group referencing missing release",
+			Folder: "broken-release-group",
+			Error:  "validating release groups: checkout ReleaseGroup is referencing missing payments Release",
+		},
 	}
 
 	for _, tc := range cases {
@@ -99,6 +108,70 @@ func TestFreeformEnvsAndReleasesLoading(t *testing.T) {
 	requireRelease(t, rels[2], "production-release", "", "1.1.1")
 }
 
+func TestLoadOptions(t *testing.T) {
+	catalogDir, err := filepath.Abs("testdata/freeform")
+	require.NoError(t, err)
+
+	t.Run("environments and releases", func(t *testing.T) {
+		cat, err := Load(context.Background(), catalogDir, nil, OnlyEnvironments("production"), OnlyReleases("common-release", "dev-release"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"production"}, cat.GetEnvironmentNames())
+		require.Len(t, cat.Projects, 2)
+		require.Equal(t, []string{"common-release"}, cat.GetReleaseNames())
+	})
+
+	t.Run("environment dir prefix of another", func(t *testing.T) {
+		catalogDir := t.TempDir()
+		require.NoError(t, os.CopyFS(catalogDir, os.DirFS("testdata/freeform")))
+
+		euDir := filepath.Join(catalogDir, "some-dir/prod-eu")
+		require.NoError(t, os.MkdirAll(euDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(euDir, "env.yaml"), []byte("apiVersion: joy.nesto.ca/v1alpha1\nkind: Environment\nmetadata:\n  name: production-eu\nspec:\n  order: 3\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(euDir, "rel.yaml"), []byte("apiVersion: joy.nesto.ca/v1alpha1\nkind: Release\nmetadata:\n  name: eu-release\nspec:\n  version: 3.3.3\n  project: project1\n"), 0o644))
+
+		cat, err := Load(context.Background(), catalogDir, nil, OnlyEnvironments("production"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"production"}, cat.GetEnvironmentNames())
+		require.Equal(t, []string{"common-release", "production-release"}, cat.GetReleaseNames())
+
+		cat, err = Load(context.Background(), catalogDir, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"dev", "production", "production-eu"}, cat.GetEnvironmentNames())
+
+		prod, err := cat.Releases.GetEnvironmentRelease(cat.Environments[1], "eu-release")
+		require.NoError(t, err)
+		require.Nil(t, prod)
+
+		eu, err := cat.Releases.GetEnvironmentRelease(cat.Environments[2], "eu-release")
+		require.NoError(t, err)
+		require.NotNil(t, eu)
+	})
+
+	t.Run("kinds", func(t *testing.T) {
+		cat, err := Load(context.Background(), catalogDir, nil, OnlyKinds(v1alpha1.ProjectKind))
+		require.NoError(t, err)
+		require.Empty(t, cat.Environments)
+		require.Empty(t, cat.GetReleaseNames())
+		require.Len(t, cat.Projects, 2)
+	})
+
+	t.Run("unknown environment", func(t *testing.T) {
+		_, err := Load(context.Background(), catalogDir, nil, OnlyEnvironments("staging"))
+		require.EqualError(t, err, "loading environments: environments not found: staging")
+	})
+
+	t.Run("skip validation", func(t *testing.T) {
+		catalogDir, err := filepath.Abs("testdata/broken-chart-ref-release")
+		require.NoError(t, err)
+
+		_, err = Load(context.Background(), catalogDir, nil)
+		require.Error(t, err)
+
+		_, err = Load(context.Background(), catalogDir, nil, SkipValidation())
+		require.NoError(t, err)
+	})
+}
+
 func TestFreeformEnvsAndReleasesLoadingWithJoyIgnore(t *testing.T) {
 	catalogDir, err := filepath.Abs("testdata/freeform-with-joyignore")
 	require.NoError(t, err)
@@ -145,6 +218,115 @@ func TestCatalogLoadIgnoresHiddenDirectories(t *testing.T) {
 	require.Equal(t, "project1", cat.Projects[0].Name)
 }
 
+func TestReleaseGroups(t *testing.T) {
+	catalogDir, err := filepath.Abs("testdata/release-groups")
+	require.NoError(t, err)
+
+	cat, err := Load(context.Background(), catalogDir, nil)
+	require.NoError(t, err)
+
+	require.Len(t, cat.ReleaseGroups, 1)
+	require.Equal(t, "checkout", cat.ReleaseGroups[0].Name)
+	require.Equal(t, "Checkout product", cat.ReleaseGroups[0].Spec.Description)
+
+	require.EqualError(t, cat.WithReleaseGroup("unknown"), `release group "unknown" not found`)
+
+	require.NoError(t, cat.WithReleaseGroup("checkout"))
+	require.Equal(t, []string{"api", "worker"}, cat.GetReleaseNames())
+}
+
+func TestReleasePatterns(t *testing.T) {
+	catalogDir, err := filepath.Abs("testdata/release-groups")
+	require.NoError(t, err)
+
+	cat, err := Load(context.Background(), catalogDir, nil)
+	require.NoError(t, err)
+
+	names, err := cat.ExpandReleasePatterns([]string{"unknown", "/^(api|other)$/"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"unknown", "api", "other"}, names)
+
+	_, err = cat.ExpandReleasePatterns([]string{"payments-*"})
+	require.EqualError(t, err, "no releases matching: payments-*")
+
+	require.EqualError(t, cat.WithReleasePatterns([]string{"w*", "unknown"}), "no releases matching: unknown")
+	require.NoError(t, cat.WithReleasePatterns([]string{"w*", "api"}))
+	require.Equal(t, []string{"api", "worker"}, cat.GetReleaseNames())
+}
+
+func TestLoadWithCache(t *testing.T) {
+	catalogDir := t.TempDir()
+	require.NoError(t, os.CopyFS(catalogDir, os.DirFS("testdata/release-groups")))
+	require.NoError(t, os.WriteFile(filepath.Join(catalogDir, "notes.yaml"), []byte("todo: nothing"), 0o644))
+
+	cacheDir := t.TempDir()
+
+	uncached, err := Load(context.Background(), catalogDir, nil)
+	require.NoError(t, err)
+
+	for range 2 {
+		cached, err := Load(context.Background(), catalogDir, nil, WithCache(cacheDir))
+		require.NoError(t, err)
+		require.Equal(t, uncached.GetEnvironmentNames(), cached.GetEnvironmentNames())
+		require.Equal(t, uncached.GetReleaseNames(), cached.GetReleaseNames())
+		require.Len(t, cached.Files, len(uncached.Files))
+	}
+
+	cacheFiles, err := filepath.Glob(filepath.Join(cacheDir, "catalog", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, cacheFiles, 1)
+
+	// Changed files are validated again
+	envFile := filepath.Join(catalogDir, "environments/dev/env.yaml")
+	env, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+
+	invalid := string(env) + "  promotion:\n    gates:\n      blockedDays: [Funday]\n"
+	require.NoError(t, os.WriteFile(envFile, []byte(invalid), 0o644))
+
+	_, err = Load(context.Background(), catalogDir, nil, WithCache(cacheDir))
+	require.ErrorContains(t, err, "validating environments: dev:")
+
+	require.NoError(t, os.WriteFile(envFile, env, 0o644))
+
+	_, err = Load(context.Background(), catalogDir, nil, WithCache(cacheDir))
+	require.NoError(t, err)
+}
+
+func TestLoadAtRevision(t *testing.T) {
+	repoDir := t.TempDir()
+	catalogDir := filepath.Join(repoDir, "catalog")
+	require.NoError(t, os.CopyFS(catalogDir, os.DirFS("testdata/freeform")))
+
+	repo, err := git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, worktree.AddGlob("."))
+	_, err = worktree.Commit("initial catalog", &git.CommitOptions{
+		Author: &object.Signature{Name: "joy", Email: "joy@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	// Working copy changes are not visible at the committed revision
+	require.NoError(t, os.RemoveAll(filepath.Join(catalogDir, "some-dir")))
+
+	current, err := Load(context.Background(), catalogDir, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev"}, current.GetEnvironmentNames())
+
+	committed, err := Load(context.Background(), catalogDir, nil, AtRevision("HEAD"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev", "production"}, committed.GetEnvironmentNames())
+	require.Equal(t, []string{"common-release", "dev-release", "production-release"}, committed.GetReleaseNames())
+	require.Equal(t, filepath.Join(catalogDir, "some-dir/prod/env.yaml"), committed.Environments[1].File.Path)
+
+	_, err = Load(context.Background(), catalogDir, nil, AtRevision("unknown"))
+	require.ErrorContains(t, err, "loading files at revision unknown: resolving revision")
+}
+
 func TestHasHiddenDirSegment(t *testing.T) {
 	root := filepath.Join(string(filepath.Separator), "Users", "someone", ".joy")
 
@@ -0,0 +1,155 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"gopkg.in/godo.v2/glob"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/ignore"
+	"github.com/nestoca/joy/internal/yml"
+)
+
+// listFiles returns the paths of the yaml files of the catalog in given directory, excluding those within hidden
+// directories or ignored by .joyignore.
+func listFiles(dir string) ([]string, error) {
+	// Find all files matching the glob expression
+	globExpr := filepath.Join(dir, "**/*.yaml")
+	fileAssets, _, err := glob.Glob([]string{globExpr})
+	if err != nil {
+		return nil, fmt.Errorf("matching files with glob expression %s: %w", globExpr, err)
+	}
+
+	// Load .joyignore if it exists
+	ignoreMatcher, err := ignore.NewMatcher(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading .joyignore: %w", err)
+	}
+
+	var paths []string
+	for _, fileAsset := range fileAssets {
+		// Skip files living inside a hidden directory (e.g. .git, .claude).
+		// Nested git worktrees and checkouts are commonly placed under such
+		// folders (for example .claude/worktrees/<name>) and each contains its
+		// own full copy of the catalog. Without this, every environment,
+		// release and project would be loaded once per checkout, duplicating
+		// the whole catalog N times.
+		if hidden, err := hasHiddenDirSegment(dir, fileAsset.Path); err != nil {
+			return nil, err
+		} else if hidden {
+			continue
+		}
+
+		if ignoreMatcher != nil && ignoreMatcher.Match(fileAsset.Path) {
+			continue
+		}
+
+		paths = append(paths, fileAsset.Path)
+	}
+
+	return paths, nil
+}
+
+// loadFiles concurrently parses the files at given paths, only keeping joy resources, in the order of given paths.
+// When multiple files cannot be loaded, the error of the first one is returned, such that the outcome does not depend
+// on scheduling.
+func loadFiles(paths []string, cache *fileCache) ([]*yml.File, error) {
+	files := make([]*yml.File, len(paths))
+	errs := make([]error, len(paths))
+
+	parallelize(len(paths), func(i int) {
+		files[i], errs[i] = loadFile(paths[i], cache)
+	})
+
+	var resources []*yml.File
+	for i, file := range files {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		// Only keep Joy CRDs
+		if file != nil && isValid(file) {
+			resources = append(resources, file)
+		}
+	}
+	return resources, nil
+}
+
+// loadFile parses the file at given path, returning nil for files that the cache knows not to be joy resources.
+func loadFile(path string, cache *fileCache) (*yml.File, error) {
+	var info os.FileInfo
+	if cache != nil {
+		var err error
+		if info, err = os.Stat(path); err != nil {
+			return nil, fmt.Errorf("loading yaml file %s: %w", path, err)
+		}
+		if entry, ok := cache.lookup(path, info); ok && !entry.Resource {
+			return nil, nil
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading yaml file %s: %w", path, err)
+	}
+
+	file, err := yml.NewFile(path, content)
+	if err != nil {
+		return nil, fmt.Errorf("loading yaml file %s: %w", path, err)
+	}
+
+	if cache != nil {
+		cache.update(path, info, hashContent(content), isValid(file))
+	}
+
+	return file, nil
+}
+
+// validateSchemas concurrently validates given files against the schemas of their kinds, returning the validation
+// errors by file. Files that the cache knows to be valid are skipped.
+func validateSchemas(files []*yml.File, cache *fileCache) map[*yml.File]error {
+	errs := make([]error, len(files))
+
+	parallelize(len(files), func(i int) {
+		if cache.isValid(files[i].Path) {
+			return
+		}
+		if errs[i] = v1alpha1.ValidateSchema(files[i]); errs[i] == nil {
+			cache.setValid(files[i].Path)
+		}
+	})
+
+	result := make(map[*yml.File]error, len(files))
+	for i, file := range files {
+		if errs[i] != nil {
+			result[file] = errs[i]
+		}
+	}
+	return result
+}
+
+// parallelize calls fn for each index in [0, n) using as many goroutines as there are available CPUs.
+func parallelize(n int, fn func(i int)) {
+	workers := min(runtime.GOMAXPROCS(0), n)
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,163 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/nestoca/joy/internal/ignore"
+	"github.com/nestoca/joy/internal/yml"
+)
+
+// loadFilesAtRevision concurrently parses the yaml files of the catalog in given directory as of given git revision,
+// only keeping joy resources. Files are read from the git object database and given paths within the catalog
+// directory, as if the revision was checked out.
+func loadFilesAtRevision(dir, revision string) ([]*yml.File, error) {
+	tree, err := catalogTreeAtRevision(dir, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var ignoreMatcher *ignore.Matcher
+	if file, err := tree.File(ignore.FileName); err == nil {
+		reader, err := file.Reader()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", ignore.FileName, err)
+		}
+		ignoreMatcher, err = ignore.NewMatcherFromReader(reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", ignore.FileName, err)
+		}
+	} else if !errors.Is(err, object.ErrFileNotFound) {
+		return nil, fmt.Errorf("looking up %s: %w", ignore.FileName, err)
+	}
+
+	var (
+		paths    []string
+		contents []string
+	)
+
+	// Blobs are read sequentially, as the object database is not safe for concurrent use
+	err = tree.Files().ForEach(func(file *object.File) error {
+		if !strings.HasSuffix(file.Name, ".yaml") || !file.Mode.IsFile() || file.Mode == filemode.Symlink {
+			return nil
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(file.Name))
+
+		// Skip files living inside a hidden directory, as when loading from the working copy
+		if hidden, err := hasHiddenDirSegment(dir, path); err != nil {
+			return err
+		} else if hidden {
+			return nil
+		}
+
+		if ignoreMatcher != nil && ignoreMatcher.Match(path) {
+			return nil
+		}
+
+		content, err := file.Contents()
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", file.Name, err)
+		}
+
+		paths = append(paths, path)
+		contents = append(contents, content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*yml.File, len(paths))
+	errs := make([]error, len(paths))
+
+	parallelize(len(paths), func(i int) {
+		if files[i], errs[i] = yml.NewFile(paths[i], []byte(contents[i])); errs[i] != nil {
+			errs[i] = fmt.Errorf("loading yaml file %s: %w", paths[i], errs[i])
+		}
+	})
+
+	var resources []*yml.File
+	for i, file := range files {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		// Only keep Joy CRDs
+		if isValid(file) {
+			resources = append(resources, file)
+		}
+	}
+	return resources, nil
+}
+
+// catalogTreeAtRevision returns the git tree of given catalog directory as of given revision, which may be a
+// subdirectory of its repository.
+func catalogTreeAtRevision(dir, revision string) (*object.Tree, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("opening git repository of %s: %w", dir, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("getting worktree: %w", err)
+	}
+
+	subdir, err := relativeToRoot(worktree.Filesystem.Root(), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("resolving revision: %w", err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("getting commit %s: %w", hash, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("getting tree of commit %s: %w", hash, err)
+	}
+
+	if subdir == "." {
+		return tree, nil
+	}
+
+	tree, err = tree.Tree(subdir)
+	if err != nil {
+		return nil, fmt.Errorf("getting tree of %s in commit %s: %w", subdir, hash, err)
+	}
+	return tree, nil
+}
+
+// relativeToRoot returns the slash-separated path of dir relative to the root of its repository, resolving symlinks
+// such that both are comparable.
+func relativeToRoot(root, dir string) (string, error) {
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("resolving repository root: %w", err)
+	}
+
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving catalog directory: %w", err)
+	}
+
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s relative to repository root: %w", dir, err)
+	}
+	return filepath.ToSlash(rel), nil
+}
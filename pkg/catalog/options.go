@@ -0,0 +1,120 @@
+package catalog
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/yml"
+)
+
+// LoadOption customizes how the catalog is loaded.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	cacheDir       string
+	revision       string
+	environments   []string
+	releases       []string
+	kinds          []string
+	skipValidation bool
+}
+
+// WithCache enables an index of catalog files within given joy cache directory, such that files that have not changed
+// since the last load are neither parsed again when they are not joy resources, nor validated again against their
+// schema when they previously passed validation.
+func WithCache(joyCacheDir string) LoadOption {
+	return func(opts *loadOptions) {
+		opts.cacheDir = joyCacheDir
+	}
+}
+
+// AtRevision loads the catalog as of given git revision (e.g. a branch, tag or commit), reading files directly from the
+// git object database of the repository containing the catalog directory, without touching its working copy.
+func AtRevision(revision string) LoadOption {
+	return func(opts *loadOptions) {
+		opts.revision = revision
+	}
+}
+
+// OnlyEnvironments restricts loading to the environments with given names and their releases. Loading fails if any of
+// them cannot be found.
+func OnlyEnvironments(names ...string) LoadOption {
+	return func(opts *loadOptions) {
+		opts.environments = append(opts.environments, names...)
+	}
+}
+
+// OnlyReleases restricts loading to the releases with given names. Unlike environments, releases that cannot be found
+// are simply not loaded.
+func OnlyReleases(names ...string) LoadOption {
+	return func(opts *loadOptions) {
+		opts.releases = append(opts.releases, names...)
+	}
+}
+
+// OnlyKinds restricts loading to resources of given kinds, along with the kinds they depend on: releases require their
+// environments and projects, while release groups require releases.
+func OnlyKinds(kinds ...string) LoadOption {
+	return func(opts *loadOptions) {
+		opts.kinds = append(opts.kinds, kinds...)
+	}
+}
+
+// SkipValidation skips validating resources against their schemas, as well as their chart references, tags and
+// release group members, for commands that only read the catalog.
+func SkipValidation() LoadOption {
+	return func(opts *loadOptions) {
+		opts.skipValidation = true
+	}
+}
+
+// requiredKinds returns the kinds of resources to load, or nil for all of them.
+func (opts loadOptions) requiredKinds() []string {
+	if len(opts.kinds) == 0 {
+		return nil
+	}
+
+	kinds := slices.Clone(opts.kinds)
+	if slices.Contains(kinds, v1alpha1.ReleaseGroupKind) {
+		kinds = append(kinds, v1alpha1.ReleaseKind)
+	}
+	if slices.Contains(kinds, v1alpha1.ReleaseKind) {
+		kinds = append(kinds, v1alpha1.EnvironmentKind, v1alpha1.ProjectKind)
+	}
+	return kinds
+}
+
+// isPartial returns whether only a subset of the releases may be loaded, in which case references to releases cannot be
+// verified.
+func (opts loadOptions) isPartial() bool {
+	return len(opts.environments) > 0 || len(opts.releases) > 0
+}
+
+// filterFiles returns the files of the resources to load, before environments are known.
+func (opts loadOptions) filterFiles(files []*yml.File) []*yml.File {
+	kinds := opts.requiredKinds()
+	return slices.DeleteFunc(files, func(file *yml.File) bool {
+		switch {
+		case kinds != nil && !slices.Contains(kinds, file.Kind):
+			return true
+		case file.Kind == v1alpha1.EnvironmentKind && len(opts.environments) > 0:
+			return !slices.Contains(opts.environments, file.MetadataName)
+		case file.Kind == v1alpha1.ReleaseKind && len(opts.releases) > 0:
+			return !slices.Contains(opts.releases, file.MetadataName)
+		default:
+			return false
+		}
+	})
+}
+
+// filterReleaseFiles removes the files of releases not belonging to any of given environments. Directories are
+// compared up to a path separator, such that releases of environment dir prod-eu do not belong to environment dir prod.
+func filterReleaseFiles(files []*yml.File, environments []*v1alpha1.Environment) []*yml.File {
+	return slices.DeleteFunc(files, func(file *yml.File) bool {
+		return file.Kind == v1alpha1.ReleaseKind && !slices.ContainsFunc(environments, func(env *v1alpha1.Environment) bool {
+			return strings.HasPrefix(file.Path, env.Dir+string(filepath.Separator))
+		})
+	})
+}
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/davidmdm/x/xfs"
 
@@ -27,28 +29,52 @@ type ChartCache struct {
 	DefaultChartRef string
 	Root            string
 	Puller
+
+	// Lock, if any, pins the registry and content digests of chart versions that do not specify them.
+	Lock *ChartLock
+
+	// Mirror, if any, is where charts missing from the cache are looked up before pulling them: either a read-only
+	// directory with the same layout as the cache, or a bundle produced by WriteBundle.
+	Mirror string
+
+	// Offline prevents pulling charts, such that charts neither cached nor mirrored fail with ErrChartUnavailable.
+	Offline bool
 }
 
+var ErrChartUnavailable = errors.New("chart not available offline")
+
 func (cache ChartCache) GetReleaseChart(release *v1alpha1.Release) (Chart, error) {
 	if repoURL := release.Spec.Chart.RepoUrl; repoURL != "" {
-		return Chart{
+		chart := Chart{
 			RepoURL:  repoURL,
 			Name:     release.Spec.Chart.Name,
 			Version:  release.Spec.Chart.Version,
 			Mappings: release.Spec.Chart.Mappings,
-		}, nil
+		}
+		chart.Digest = cache.Lock.Digest(chart)
+		chart.ContentDigest = cache.Lock.ContentDigest(chart)
+		return chart, nil
 	}
 
 	ref := cmp.Or(release.Spec.Chart.Ref, cache.DefaultChartRef)
 
 	chart := cache.Refs[ref]
 
-	chart.Version = cmp.Or(
+	version := cmp.Or(
 		release.Spec.Chart.Version,
 		release.Environment.Spec.ChartVersions[ref],
 		chart.Version,
 	)
 
+	// The digests of a chart reference only pin its own version
+	if version != chart.Version {
+		chart.Version = version
+		chart.Digest = ""
+		chart.ContentDigest = ""
+	}
+	chart.Digest = cmp.Or(chart.Digest, cache.Lock.Digest(chart))
+	chart.ContentDigest = cmp.Or(chart.ContentDigest, cache.Lock.ContentDigest(chart))
+
 	chart.Mappings = func() map[string]any {
 		mappings := make(map[string]any)
 		maps.Copy(mappings, chart.Mappings)
@@ -64,7 +90,11 @@ func (cache ChartCache) GetReleaseChartFS(ctx context.Context, release *v1alpha1
 	if err != nil {
 		return nil, err
 	}
+	return cache.GetChartFS(ctx, chart)
+}
 
+// GetChartFS returns the files of given chart, pulling it into the cache first if it is not there yet.
+func (cache ChartCache) GetChartFS(ctx context.Context, chart Chart) (*ChartFS, error) {
 	uri, err := chart.ToURL()
 	if err != nil {
 		return nil, fmt.Errorf("computing chart URL: %w", err)
@@ -77,23 +107,117 @@ func (cache ChartCache) GetReleaseChartFS(ctx context.Context, release *v1alpha1
 		}, nil
 	}
 
-	versionDir := filepath.Join(cache.Root, uri.Host, uri.Path, chart.Version)
-	if err := os.MkdirAll(versionDir, 0o755); err != nil {
-		return nil, err
-	}
+	chartDir := cache.chartDir(uri, chart.Version)
 
-	chartDir := filepath.Join(versionDir, path.Base(uri.Path))
+	// Releases sharing the same chart and version may be fetched concurrently, in which case the chart is pulled only
+	// once while the others wait for it.
+	unlock := lockChartDir(chartDir)
+	defer unlock()
 
 	if _, err := os.Stat(chartDir); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			return nil, fmt.Errorf("verifying cache: %w", err)
 		}
 
-		pullOptions := PullOptions{Chart: chart, OutputDir: versionDir}
-		if err := cache.Pull(ctx, pullOptions); err != nil {
+		mirrorDir, err := cache.fetchFromMirror(chart, chartDir)
+		if err != nil {
+			return nil, fmt.Errorf("fetching chart from mirror: %w", err)
+		}
+		if mirrorDir != "" {
+			return &ChartFS{FS: xfs.Dir(mirrorDir), Chart: chart}, nil
+		}
+
+		if cache.Offline {
+			return nil, fmt.Errorf("%w: %s@%s: run joy cache charts warm while online or configure a chart mirror", ErrChartUnavailable, path.Join(uri.Host, uri.Path), chart.Version)
+		}
+
+		if err := cache.pull(ctx, chart, chartDir); err != nil {
 			return nil, fmt.Errorf("pulling chart: %w", err)
 		}
+	} else if chart.Digest != "" || chart.ContentDigest != "" {
+		if err := verifyChartDir(chart, chartDir); err != nil {
+			return nil, fmt.Errorf("verifying cache: %w", err)
+		}
 	}
 
 	return &ChartFS{FS: xfs.Dir(chartDir), Chart: chart}, nil
 }
+
+// ChartDir returns the directory of given chart within the cache, or an empty string for local charts, which are used
+// in place rather than cached.
+func (cache ChartCache) ChartDir(chart Chart) (string, error) {
+	uri, err := chart.ToURL()
+	if err != nil {
+		return "", fmt.Errorf("computing chart URL: %w", err)
+	}
+	if uri.Scheme == "file" {
+		return "", nil
+	}
+	return cache.chartDir(uri, chart.Version), nil
+}
+
+func (cache ChartCache) chartDir(uri *url.URL, version string) string {
+	return filepath.Join(cache.Root, uri.Host, uri.Path, version, path.Base(uri.Path))
+}
+
+// pull pulls the chart into the cache.
+func (cache ChartCache) pull(ctx context.Context, chart Chart, chartDir string) error {
+	return cache.store(chart, chartDir, func(pullDir string) (string, error) {
+		result, err := cache.Pull(ctx, PullOptions{Chart: chart, OutputDir: pullDir})
+		return result.Digest, err
+	})
+}
+
+// store fetches the chart into a temporary directory next to its final location, where it is only moved once complete,
+// along with its digests, such that an interrupted fetch never leaves a partial chart in the cache. The fetch function
+// returns the registry digest of the chart, if known.
+func (cache ChartCache) store(chart Chart, chartDir string, fetch func(pullDir string) (string, error)) error {
+	versionDir := filepath.Dir(chartDir)
+	if err := os.MkdirAll(versionDir, 0o755); err != nil {
+		return err
+	}
+
+	pullDir, err := os.MkdirTemp(versionDir, pullDirPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("creating pull directory: %w", err)
+	}
+	defer os.RemoveAll(pullDir)
+
+	registryDigest, err := fetch(pullDir)
+	if err != nil {
+		return err
+	}
+	if err := verifyRegistryDigest(chart, registryDigest); err != nil {
+		return err
+	}
+	if registryDigest != "" {
+		if err := os.WriteFile(registryDigestPath(chartDir), []byte(registryDigest+"\n"), 0o644); err != nil {
+			return fmt.Errorf("writing registry digest: %w", err)
+		}
+	}
+
+	pulledDir := filepath.Join(pullDir, filepath.Base(chartDir))
+
+	digest, err := DigestDir(pulledDir)
+	if err != nil {
+		return fmt.Errorf("computing digest: %w", err)
+	}
+	if err := verifyDigest(chart, digest); err != nil {
+		return err
+	}
+	if err := os.WriteFile(digestPath(chartDir), []byte(digest+"\n"), 0o644); err != nil {
+		return fmt.Errorf("writing digest: %w", err)
+	}
+
+	return os.Rename(pulledDir, chartDir)
+}
+
+// chartDirLocks holds a mutex per chart directory of the cache, shared by all chart caches of the process.
+var chartDirLocks sync.Map
+
+func lockChartDir(dir string) (unlock func()) {
+	value, _ := chartDirLocks.LoadOrStore(dir, new(sync.Mutex))
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
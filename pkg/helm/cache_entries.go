@@ -0,0 +1,300 @@
+package helm
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+const (
+	// pullDirPrefix is the prefix of the temporary directories charts are pulled into, which are only left behind by
+	// interrupted pulls.
+	pullDirPrefix = ".pull-"
+
+	digestSuffix = ".sha256"
+
+	// registryDigestSuffix is the suffix of the file recording the digest of a chart in its registry, when known.
+	registryDigestSuffix = ".digest"
+
+	// contentDigestPrefix distinguishes digests of the files of charts from the digests of charts in their registry.
+	contentDigestPrefix = "content-sha256:"
+)
+
+type ChartStatus string
+
+const (
+	// ChartValid is for charts matching the digest recorded when they were pulled.
+	ChartValid ChartStatus = "valid"
+
+	// ChartModified is for charts whose files changed since they were pulled.
+	ChartModified ChartStatus = "modified"
+
+	// ChartUnverified is for charts without a recorded digest, such as those pulled by older versions of joy.
+	ChartUnverified ChartStatus = "unverified"
+
+	// ChartIncomplete is for the leftovers of interrupted pulls.
+	ChartIncomplete ChartStatus = "incomplete"
+)
+
+// CachedChart is a version of a chart stored in the chart cache.
+type CachedChart struct {
+	// Ref is the location of the chart, without scheme, such as ghcr.io/acme/charts/app.
+	Ref     string `json:"ref" yaml:"ref"`
+	Version string `json:"version" yaml:"version"`
+
+	// Dir is the directory of the chart, or of the interrupted pull for incomplete charts.
+	Dir  string `json:"dir" yaml:"dir"`
+	Size int64  `json:"size" yaml:"size"`
+
+	// Incomplete is whether this is the leftover of an interrupted pull.
+	Incomplete bool `json:"incomplete,omitempty" yaml:"incomplete,omitempty"`
+}
+
+// List returns the charts stored in the cache, sorted by ref and version.
+//
+// The cache root is shared with other joy caches, so only top-level directories named like registry hosts are
+// searched, and charts are recognized by their location: <host>/<path>/<version>/<base name of path>.
+func (cache ChartCache) List() ([]CachedChart, error) {
+	entries, err := os.ReadDir(cache.Root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	var charts []CachedChart
+	for _, entry := range entries {
+		if !entry.IsDir() || !isHostName(entry.Name()) {
+			continue
+		}
+
+		err := filepath.WalkDir(filepath.Join(cache.Root, entry.Name()), func(dir string, entry fs.DirEntry, err error) error {
+			if err != nil || !entry.IsDir() {
+				return err
+			}
+
+			incomplete := strings.HasPrefix(entry.Name(), pullDirPrefix)
+			if !incomplete && !isChartDir(dir) {
+				return nil
+			}
+
+			versionDir := filepath.Dir(dir)
+			ref, err := filepath.Rel(cache.Root, filepath.Dir(versionDir))
+			if err != nil {
+				return err
+			}
+
+			size, err := dirSize(dir)
+			if err != nil {
+				return fmt.Errorf("computing size of %s: %w", dir, err)
+			}
+
+			charts = append(charts, CachedChart{
+				Ref:        filepath.ToSlash(ref),
+				Version:    filepath.Base(versionDir),
+				Dir:        dir,
+				Size:       size,
+				Incomplete: incomplete,
+			})
+
+			return filepath.SkipDir
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing cached charts: %w", err)
+		}
+	}
+
+	slices.SortFunc(charts, func(a, b CachedChart) int {
+		return cmp.Or(cmp.Compare(a.Ref, b.Ref), cmp.Compare(a.Version, b.Version), cmp.Compare(a.Dir, b.Dir))
+	})
+
+	return charts, nil
+}
+
+// Verify compares the files of given cached chart to the digest recorded when it was pulled.
+func (cache ChartCache) Verify(chart CachedChart) (ChartStatus, error) {
+	if chart.Incomplete {
+		return ChartIncomplete, nil
+	}
+
+	expected, err := os.ReadFile(digestPath(chart.Dir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ChartUnverified, nil
+		}
+		return "", fmt.Errorf("reading digest: %w", err)
+	}
+
+	actual, err := DigestDir(chart.Dir)
+	if err != nil {
+		return "", fmt.Errorf("computing digest: %w", err)
+	}
+
+	if actual != strings.TrimSpace(string(expected)) {
+		return ChartModified, nil
+	}
+	return ChartValid, nil
+}
+
+// Remove removes given chart from the cache, along with its digest and the directories left empty.
+func (cache ChartCache) Remove(chart CachedChart) error {
+	unlock := lockChartDir(chart.Dir)
+	defer unlock()
+
+	if err := os.RemoveAll(chart.Dir); err != nil {
+		return err
+	}
+	for _, file := range []string{digestPath(chart.Dir), registryDigestPath(chart.Dir)} {
+		if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	root := filepath.Clean(cache.Root)
+	for dir := filepath.Dir(chart.Dir); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		// Removing a directory that is not empty fails, which is where cleanup stops
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// CatalogCharts returns the charts referenced by given releases, along with the chart versions pinned by given
+// environments, sorted and without duplicates. Local charts are omitted as they are not cached.
+func (cache ChartCache) CatalogCharts(releases []*v1alpha1.Release, environments []*v1alpha1.Environment) ([]Chart, error) {
+	charts := map[string]Chart{}
+
+	add := func(chart Chart) error {
+		dir, err := cache.ChartDir(chart)
+		if err != nil {
+			return fmt.Errorf("%s/%s: %w", chart.RepoURL, chart.Name, err)
+		}
+		if dir != "" {
+			// Mappings are irrelevant to which chart files are needed
+			chart.Mappings = nil
+			charts[dir] = chart
+		}
+		return nil
+	}
+
+	for _, release := range releases {
+		chart, err := cache.GetReleaseChart(release)
+		if err != nil {
+			return nil, fmt.Errorf("getting chart of release %s: %w", release.Name, err)
+		}
+		if err := add(chart); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, env := range environments {
+		for ref, version := range env.Spec.ChartVersions {
+			chart, ok := cache.Refs[ref]
+			if !ok {
+				continue
+			}
+			chart.Version = version
+			if err := add(chart); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := make([]Chart, 0, len(charts))
+	for _, chart := range charts {
+		result = append(result, chart)
+	}
+	slices.SortFunc(result, func(a, b Chart) int {
+		return cmp.Or(cmp.Compare(a.RepoURL, b.RepoURL), cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+	})
+
+	return result, nil
+}
+
+// DigestDir returns a digest of the relative paths and contents of the regular files within given directory, in the
+// form content-sha256:<hex>. Its value differs from the digest of the chart in its registry.
+func DigestDir(dir string) (string, error) {
+	hash := sha256.New()
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		fileHash := sha256.New()
+		if _, err := io.Copy(fileHash, file); err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(hash, "%s %x\n", filepath.ToSlash(rel), fileHash.Sum(nil))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return contentDigestPrefix + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func digestPath(chartDir string) string {
+	return chartDir + digestSuffix
+}
+
+func registryDigestPath(chartDir string) string {
+	return chartDir + registryDigestSuffix
+}
+
+// isChartDir returns whether given directory is a chart stored at <path>/<version>/<base name of path>.
+func isChartDir(dir string) bool {
+	if filepath.Base(dir) != filepath.Base(filepath.Dir(filepath.Dir(dir))) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, "Chart.yaml"))
+	return err == nil
+}
+
+// isHostName returns whether given name could be the host of a chart registry or repository, as opposed to the other
+// caches sharing the root of the chart cache.
+func isHostName(name string) bool {
+	return strings.ContainsAny(name, ".:") || name == "localhost"
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,123 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+func TestChartCache(t *testing.T) {
+	root := t.TempDir()
+
+	puller := &PullRendererMock{
+		PullFunc: func(ctx context.Context, opts PullOptions) (PullResult, error) {
+			if opts.Chart.Version == "0.0.0" {
+				// Simulate a pull interrupted halfway through
+				_ = os.MkdirAll(filepath.Join(opts.OutputDir, opts.Chart.Name), 0o755)
+				return PullResult{}, errors.New("connection reset")
+			}
+			dir := filepath.Join(opts.OutputDir, opts.Chart.Name)
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: "+opts.Chart.Name), 0o644))
+			return PullResult{}, os.WriteFile(filepath.Join(dir, "templates", "app.yaml"), []byte("kind: Deployment"), 0o644)
+		},
+	}
+
+	cache := ChartCache{
+		Refs:   map[string]Chart{"app": {RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "1.0.0"}},
+		Root:   root,
+		Puller: puller,
+	}
+
+	// Other caches share the same root
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src", "app", "chart", "1.0.0", "chart"), 0o755))
+
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		_, err := cache.GetChartFS(context.Background(), Chart{RepoURL: "ghcr.io/acme/charts", Name: "app", Version: version})
+		require.NoError(t, err)
+	}
+
+	_, err := cache.GetChartFS(context.Background(), Chart{RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "0.0.0"})
+	require.ErrorContains(t, err, "connection reset")
+
+	_, err = os.Stat(filepath.Join(root, "ghcr.io", "acme", "charts", "app", "0.0.0", "app"))
+	require.ErrorIs(t, err, os.ErrNotExist, "interrupted pull should not leave a partial chart")
+
+	// Already cached charts are not pulled again
+	_, err = cache.GetChartFS(context.Background(), Chart{RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "1.0.0"})
+	require.NoError(t, err)
+	require.Len(t, puller.PullCalls(), 3)
+
+	charts, err := cache.List()
+	require.NoError(t, err)
+	require.Len(t, charts, 2)
+
+	for i, version := range []string{"1.0.0", "2.0.0"} {
+		require.Equal(t, "ghcr.io/acme/charts/app", charts[i].Ref)
+		require.Equal(t, version, charts[i].Version)
+		require.EqualValues(t, len("name: app")+len("kind: Deployment"), charts[i].Size)
+
+		status, err := cache.Verify(charts[i])
+		require.NoError(t, err)
+		require.Equal(t, ChartValid, status)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(charts[1].Dir, "templates", "app.yaml"), []byte("kind: Job"), 0o644))
+	status, err := cache.Verify(charts[1])
+	require.NoError(t, err)
+	require.Equal(t, ChartModified, status)
+
+	require.NoError(t, os.Remove(digestPath(charts[0].Dir)))
+	status, err = cache.Verify(charts[0])
+	require.NoError(t, err)
+	require.Equal(t, ChartUnverified, status)
+
+	require.NoError(t, cache.Remove(charts[1]))
+	_, err = os.Stat(filepath.Join(root, "ghcr.io", "acme", "charts", "app", "2.0.0"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	charts, err = cache.List()
+	require.NoError(t, err)
+	require.Len(t, charts, 1)
+	require.Equal(t, "1.0.0", charts[0].Version)
+}
+
+func TestCatalogCharts(t *testing.T) {
+	cache := ChartCache{
+		Refs: map[string]Chart{
+			"app":   {RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "1.0.0"},
+			"local": {RepoURL: "file://./charts", Name: "local"},
+		},
+		DefaultChartRef: "app",
+		Root:            t.TempDir(),
+	}
+
+	env := &v1alpha1.Environment{Spec: v1alpha1.EnvironmentSpec{ChartVersions: map[string]string{"app": "1.1.0"}}}
+
+	release := func(chart v1alpha1.ReleaseChart) *v1alpha1.Release {
+		return &v1alpha1.Release{Spec: v1alpha1.ReleaseSpec{Chart: chart}, Environment: &v1alpha1.Environment{}}
+	}
+
+	charts, err := cache.CatalogCharts(
+		[]*v1alpha1.Release{
+			release(v1alpha1.ReleaseChart{}),
+			release(v1alpha1.ReleaseChart{Ref: "app"}),
+			release(v1alpha1.ReleaseChart{Ref: "local"}),
+			release(v1alpha1.ReleaseChart{RepoUrl: "https://charts.acme.com", Name: "other", Version: "3.0.0"}),
+		},
+		[]*v1alpha1.Environment{env},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, []Chart{
+		{RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "1.0.0"},
+		{RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "1.1.0"},
+		{RepoURL: "https://charts.acme.com", Name: "other", Version: "3.0.0"},
+	}, charts)
+}
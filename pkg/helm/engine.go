@@ -0,0 +1,31 @@
+package helm
+
+import (
+	"fmt"
+
+	"github.com/nestoca/joy/internal"
+)
+
+// Engine selects the implementation of PullRenderer.
+type Engine string
+
+const (
+	// EngineCLI runs the helm binary found on PATH, and is the default.
+	EngineCLI Engine = "cli"
+
+	// EngineSDK pulls and renders charts in-process with the Helm Go SDK, which requires joy to be built with the
+	// helmsdk build tag.
+	EngineSDK Engine = "sdk"
+)
+
+// NewPullRenderer returns the PullRenderer of given engine, defaulting to the helm CLI.
+func NewPullRenderer(engine Engine, io internal.IO, debug bool) (PullRenderer, error) {
+	switch engine {
+	case "", EngineCLI:
+		return CLI{IO: io, Debug: debug}, nil
+	case EngineSDK:
+		return newSDK(io, debug)
+	default:
+		return nil, fmt.Errorf("unknown helm engine %q: must be one of %q or %q", engine, EngineCLI, EngineSDK)
+	}
+}
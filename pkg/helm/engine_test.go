@@ -0,0 +1,20 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal"
+)
+
+func TestNewPullRenderer(t *testing.T) {
+	for _, engine := range []Engine{"", EngineCLI} {
+		pullRenderer, err := NewPullRenderer(engine, internal.IO{}, true)
+		require.NoError(t, err)
+		require.Equal(t, CLI{Debug: true}, pullRenderer)
+	}
+
+	_, err := NewPullRenderer("kubectl", internal.IO{}, false)
+	require.EqualError(t, err, `unknown helm engine "kubectl": must be one of "cli" or "sdk"`)
+}
@@ -5,8 +5,10 @@ import (
 	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"os/exec"
 	"path"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -16,7 +18,7 @@ import (
 )
 
 type Puller interface {
-	Pull(context.Context, PullOptions) error
+	Pull(context.Context, PullOptions) (PullResult, error)
 }
 
 //go:generate moq -stub -out ./pull_renderer_mock.go . PullRenderer
@@ -35,7 +37,16 @@ type PullOptions struct {
 	OutputDir string
 }
 
-func (cli CLI) Pull(ctx context.Context, opts PullOptions) error {
+type PullResult struct {
+	// Digest is the digest of the chart in its registry, in the form sha256:<hex>, as reported when pulling OCI charts.
+	// It is empty for charts of HTTP repositories, which do not report one.
+	Digest string
+}
+
+// pulledDigestRegex matches the digest helm reports when pulling OCI charts, which is the same as reported by helm push.
+var pulledDigestRegex = regexp.MustCompile(`(?m)^Digest: (sha256:[0-9a-f]{64})\s*$`)
+
+func (cli CLI) Pull(ctx context.Context, opts PullOptions) (PullResult, error) {
 	ctx, span := observability.StartTrace(ctx, "helm_pull")
 	defer span.End()
 
@@ -45,7 +56,7 @@ func (cli CLI) Pull(ctx context.Context, opts PullOptions) error {
 
 	chartURL, err := opts.Chart.ToURL()
 	if err != nil {
-		return fmt.Errorf("invalid chart url: %w", err)
+		return PullResult{}, fmt.Errorf("invalid chart url: %w", err)
 	}
 
 	var args []string
@@ -63,15 +74,30 @@ func (cli CLI) Pull(ctx context.Context, opts PullOptions) error {
 		args = append(args, "--version", version)
 	}
 
+	// Output is captured for the digest, which depending on the version of helm is reported on stdout or stderr
+	var output bytes.Buffer
+
 	cmd := exec.CommandContext(ctx, "helm", args...)
-	cmd.Stdout = cli.Out
-	cmd.Stderr = cli.Err
+	cmd.Stdout = teeWriter(cli.Out, &output)
+	cmd.Stderr = teeWriter(cli.Err, &output)
 	cmd.Stdin = cli.In
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("running %s: %w", strings.Join(cmd.Args, " "), err)
+		return PullResult{}, fmt.Errorf("running %s: %w", strings.Join(cmd.Args, " "), err)
+	}
+
+	var result PullResult
+	if match := pulledDigestRegex.FindStringSubmatch(output.String()); match != nil {
+		result.Digest = match[1]
+	}
+	return result, nil
+}
+
+func teeWriter(w io.Writer, output *bytes.Buffer) io.Writer {
+	if w == nil {
+		return output
 	}
-	return nil
+	return io.MultiWriter(w, output)
 }
 
 type RenderOpts struct {
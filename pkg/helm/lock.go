@@ -0,0 +1,201 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChartLockFile is the name of the file of the catalog recording the digests of the chart versions it references.
+const ChartLockFile = "charts.lock"
+
+var (
+	ErrDigestMismatch        = errors.New("chart digest mismatch")
+	ErrContentDigestMismatch = errors.New("chart content digest mismatch")
+)
+
+// ChartLock records the digests of every chart version referenced by the catalog, such that a chart changing in its
+// registry without a new version, as mutable tags allow, fails rather than silently changing what gets rendered.
+type ChartLock struct {
+	Charts []LockedChart `json:"charts" yaml:"charts"`
+}
+
+type LockedChart struct {
+	RepoURL string `json:"repoUrl" yaml:"repoUrl"`
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+
+	// Digest is the digest of the chart in its registry, as reported by helm push, for OCI charts.
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+
+	// ContentDigest is the digest of the files of the chart, as computed by DigestDir. It is not the digest of the
+	// chart in its registry.
+	ContentDigest string `json:"contentDigest" yaml:"contentDigest"`
+}
+
+// LoadChartLock loads the chart lock at given path, returning an error satisfying errors.Is(err, fs.ErrNotExist) when
+// there is none.
+func LoadChartLock(path string) (*ChartLock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock ChartLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// Save writes the chart lock to given path, with charts sorted such that the file is stable.
+func (lock *ChartLock) Save(path string) error {
+	slices.SortFunc(lock.Charts, func(a, b LockedChart) int {
+		return strings.Compare(a.RepoURL+"/"+a.Name+"@"+a.Version, b.RepoURL+"/"+b.Name+"@"+b.Version)
+	})
+
+	var buffer bytes.Buffer
+	buffer.WriteString("# Generated by joy charts lock. DO NOT EDIT.\n")
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(lock); err != nil {
+		return fmt.Errorf("encoding chart lock: %w", err)
+	}
+
+	return os.WriteFile(path, buffer.Bytes(), 0o644)
+}
+
+// Digest returns the locked registry digest of given chart version, or an empty string if it is not locked or its
+// registry digest is unknown.
+func (lock *ChartLock) Digest(chart Chart) string {
+	return lock.lookup(chart).Digest
+}
+
+// ContentDigest returns the locked content digest of given chart version, or an empty string if it is not locked.
+func (lock *ChartLock) ContentDigest(chart Chart) string {
+	return lock.lookup(chart).ContentDigest
+}
+
+func (lock *ChartLock) lookup(chart Chart) LockedChart {
+	if lock == nil {
+		return LockedChart{}
+	}
+	for _, locked := range lock.Charts {
+		if locked.RepoURL == chart.RepoURL && locked.Name == chart.Name && locked.Version == chart.Version {
+			return locked
+		}
+	}
+	return LockedChart{}
+}
+
+// Equal returns whether both locks record the same digests, regardless of order.
+func (lock *ChartLock) Equal(other *ChartLock) bool {
+	if lock == nil || other == nil {
+		return lock == other
+	}
+	if len(lock.Charts) != len(other.Charts) {
+		return false
+	}
+	for _, locked := range lock.Charts {
+		if other.lookup(Chart{RepoURL: locked.RepoURL, Name: locked.Name, Version: locked.Version}) != locked {
+			return false
+		}
+	}
+	return true
+}
+
+// LockChart returns the lock entry of given chart version, with its registry digest, if known, and its content
+// digest, pulling it into the cache if needed.
+func (cache ChartCache) LockChart(ctx context.Context, chart Chart) (LockedChart, error) {
+	chartFS, err := cache.GetChartFS(ctx, chart)
+	if err != nil {
+		return LockedChart{}, err
+	}
+
+	locked := LockedChart{RepoURL: chart.RepoURL, Name: chart.Name, Version: chart.Version}
+
+	if locked.Digest, err = cachedRegistryDigest(chartFS.DirName()); err != nil {
+		return LockedChart{}, err
+	}
+	if locked.ContentDigest, err = cachedDigest(chartFS.DirName()); err != nil {
+		return LockedChart{}, err
+	}
+
+	return locked, nil
+}
+
+// cachedDigest returns the digest recorded when the chart in given directory was pulled, computing it for charts pulled
+// by older versions of joy.
+func cachedDigest(chartDir string) (string, error) {
+	data, err := os.ReadFile(digestPath(chartDir))
+	if err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("reading digest: %w", err)
+	}
+	return DigestDir(chartDir)
+}
+
+// cachedRegistryDigest returns the registry digest recorded when the chart in given directory was pulled, or an empty
+// string if unknown, as for charts of HTTP repositories or pulled by older versions of joy.
+func cachedRegistryDigest(chartDir string) (string, error) {
+	data, err := os.ReadFile(registryDigestPath(chartDir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading registry digest: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// verifyChartDir verifies the chart in given directory against the digests of the chart: its registry digest against
+// the one recorded when the chart was pulled, as it cannot be computed from its files, and its files against its
+// content digest, computing their digest anew rather than trusting the one recorded when the chart was pulled, which
+// files modified since do not affect.
+func verifyChartDir(chart Chart, chartDir string) error {
+	if chart.Digest != "" {
+		digest, err := cachedRegistryDigest(chartDir)
+		if err != nil {
+			return err
+		}
+		if err := verifyRegistryDigest(chart, digest); err != nil {
+			return err
+		}
+	}
+
+	if chart.ContentDigest == "" {
+		return nil
+	}
+	digest, err := DigestDir(chartDir)
+	if err != nil {
+		return fmt.Errorf("computing digest: %w", err)
+	}
+	return verifyDigest(chart, digest)
+}
+
+func verifyRegistryDigest(chart Chart, actual string) error {
+	if chart.Digest == "" || chart.Digest == actual {
+		return nil
+	}
+	if actual == "" {
+		return fmt.Errorf("%w: %s/%s@%s: expected %s but the registry digest is unknown, as for charts not pulled from an OCI registry or cached by older versions of joy", ErrDigestMismatch, chart.RepoURL, chart.Name, chart.Version, chart.Digest)
+	}
+	return fmt.Errorf("%w: %s/%s@%s: expected %s but got %s", ErrDigestMismatch, chart.RepoURL, chart.Name, chart.Version, chart.Digest, actual)
+}
+
+func verifyDigest(chart Chart, actual string) error {
+	if chart.ContentDigest == "" || chart.ContentDigest == actual {
+		return nil
+	}
+	return fmt.Errorf("%w: %s/%s@%s: expected %s but got %s", ErrContentDigestMismatch, chart.RepoURL, chart.Name, chart.Version, chart.ContentDigest, actual)
+}
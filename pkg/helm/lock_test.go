@@ -0,0 +1,115 @@
+package helm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+)
+
+func TestChartLock(t *testing.T) {
+	content := "kind: Deployment"
+	registryDigest := "sha256:" + strings.Repeat("a", 64)
+
+	puller := &PullRendererMock{
+		PullFunc: func(ctx context.Context, opts PullOptions) (PullResult, error) {
+			dir := filepath.Join(opts.OutputDir, opts.Chart.Name)
+			require.NoError(t, os.MkdirAll(dir, 0o755))
+			return PullResult{Digest: registryDigest}, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(content), 0o644)
+		},
+	}
+
+	cache := ChartCache{
+		Refs:            map[string]Chart{"app": {RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "1.0.0"}},
+		DefaultChartRef: "app",
+		Root:            t.TempDir(),
+		Puller:          puller,
+	}
+
+	chart := Chart{RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "1.0.0"}
+
+	locked, err := cache.LockChart(context.Background(), chart)
+	require.NoError(t, err)
+	require.Equal(t, registryDigest, locked.Digest)
+	require.Regexp(t, "^content-sha256:[0-9a-f]{64}$", locked.ContentDigest)
+
+	path := filepath.Join(t.TempDir(), ChartLockFile)
+	require.NoError(t, (&ChartLock{Charts: []LockedChart{locked}}).Save(path))
+
+	lock, err := LoadChartLock(path)
+	require.NoError(t, err)
+	require.Equal(t, registryDigest, lock.Digest(chart))
+	require.Equal(t, locked.ContentDigest, lock.ContentDigest(chart))
+
+	release := &v1alpha1.Release{Environment: &v1alpha1.Environment{}}
+
+	cache.Lock = lock
+	_, err = cache.GetReleaseChartFS(context.Background(), release)
+	require.NoError(t, err)
+
+	// Cached chart modified since it was pulled
+	chartDir, err := cache.ChartDir(chart)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("kind: Job"), 0o644))
+	_, err = cache.GetReleaseChartFS(context.Background(), release)
+	require.ErrorIs(t, err, ErrContentDigestMismatch)
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(content), 0o644))
+
+	// Versions overridden by the environment are not pinned by the lock
+	release.Environment.Spec.ChartVersions = map[string]string{"app": "1.1.0"}
+	resolved, err := cache.GetReleaseChart(release)
+	require.NoError(t, err)
+	require.Empty(t, resolved.Digest)
+	require.Empty(t, resolved.ContentDigest)
+	release.Environment.Spec.ChartVersions = nil
+
+	// Cached chart no longer matching the lock
+	cache.Lock = &ChartLock{Charts: []LockedChart{{RepoURL: chart.RepoURL, Name: chart.Name, Version: chart.Version, ContentDigest: "content-sha256:other"}}}
+	_, err = cache.GetReleaseChartFS(context.Background(), release)
+	require.ErrorIs(t, err, ErrContentDigestMismatch)
+
+	cache.Lock = &ChartLock{Charts: []LockedChart{{RepoURL: chart.RepoURL, Name: chart.Name, Version: chart.Version, Digest: "sha256:other"}}}
+	_, err = cache.GetReleaseChartFS(context.Background(), release)
+	require.ErrorIs(t, err, ErrDigestMismatch)
+
+	// Pulled chart not matching the lock is not cached
+	content = "kind: Job"
+	cache.Root = t.TempDir()
+	cache.Lock = lock
+	_, err = cache.GetReleaseChartFS(context.Background(), release)
+	require.ErrorIs(t, err, ErrContentDigestMismatch)
+
+	// Chart overwritten in its registry with the same files
+	content = "kind: Deployment"
+	registryDigest = "sha256:" + strings.Repeat("b", 64)
+	_, err = cache.GetReleaseChartFS(context.Background(), release)
+	require.ErrorIs(t, err, ErrDigestMismatch)
+
+	charts, err := cache.List()
+	require.NoError(t, err)
+	require.Empty(t, charts)
+
+	// Digests pinned by chart references take precedence over the lock
+	cache.Refs["app"] = Chart{RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "1.0.0", Digest: registryDigest}
+	resolved, err = cache.GetReleaseChart(release)
+	require.NoError(t, err)
+	require.Equal(t, registryDigest, resolved.Digest)
+
+	_, err = cache.GetReleaseChartFS(context.Background(), release)
+	require.NoError(t, err)
+}
+
+func TestPulledDigest(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("0123456789abcdef", 4)
+
+	match := pulledDigestRegex.FindStringSubmatch("Pulled: ghcr.io/acme/charts/app:1.0.0\nDigest: " + digest + "\n")
+	require.Len(t, match, 2)
+	require.Equal(t, digest, match[1])
+
+	require.Nil(t, pulledDigestRegex.FindStringSubmatch("Pulled: app-1.0.0.tgz\n"))
+}
@@ -0,0 +1,235 @@
+package helm
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var errNotMirrored = errors.New("chart not mirrored")
+
+// fetchFromMirror returns the directory of given chart within the mirror, if it is a directory, or extracts the chart
+// from the mirror into the cache, if it is a bundle. It returns an empty string if there is no mirror or the chart is
+// not part of it.
+func (cache ChartCache) fetchFromMirror(chart Chart, chartDir string) (string, error) {
+	if cache.Mirror == "" {
+		return "", nil
+	}
+
+	rel, err := filepath.Rel(cache.Root, chartDir)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(cache.Mirror)
+	if err != nil {
+		return "", err
+	}
+
+	if info.IsDir() {
+		mirrorDir := filepath.Join(cache.Mirror, rel)
+		if _, err := os.Stat(mirrorDir); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return "", nil
+			}
+			return "", err
+		}
+		// The content digest recorded by the mirror is not trusted, as the mirror is a mere copy of another cache
+		if err := verifyChartDir(chart, mirrorDir); err != nil {
+			return "", err
+		}
+		return mirrorDir, nil
+	}
+
+	err = cache.store(chart, chartDir, func(pullDir string) (string, error) {
+		return extractBundleChart(cache.Mirror, filepath.ToSlash(rel), filepath.Join(pullDir, filepath.Base(chartDir)))
+	})
+	if err != nil {
+		if errors.Is(err, errNotMirrored) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return chartDir, nil
+}
+
+// WriteBundle writes given charts of the cache, along with their digests, to a gzipped tarball with the same layout as
+// the cache, for use as the mirror of caches without access to chart registries. Charts must already be cached.
+func (cache ChartCache) WriteBundle(w io.Writer, charts []Chart) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, chart := range charts {
+		chartDir, err := cache.ChartDir(chart)
+		if err != nil {
+			return err
+		}
+		if chartDir == "" {
+			continue
+		}
+
+		if _, err := os.Stat(chartDir); err != nil {
+			return fmt.Errorf("%s/%s@%s: %w", chart.RepoURL, chart.Name, chart.Version, err)
+		}
+
+		if err := addBundleFiles(tarWriter, cache.Root, chartDir); err != nil {
+			return fmt.Errorf("%s/%s@%s: %w", chart.RepoURL, chart.Name, chart.Version, err)
+		}
+
+		for _, file := range []string{digestPath(chartDir), registryDigestPath(chartDir)} {
+			if _, err := os.Stat(file); err != nil {
+				continue
+			}
+			if err := addBundleFiles(tarWriter, cache.Root, file); err != nil {
+				return fmt.Errorf("%s/%s@%s: %w", chart.RepoURL, chart.Name, chart.Version, err)
+			}
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// addBundleFiles adds given file or directory to the tarball, named relative to the root of the cache.
+func addBundleFiles(tarWriter *tar.Writer, root, file string) error {
+	return filepath.WalkDir(file, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		source, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer source.Close()
+
+		_, err = io.Copy(tarWriter, source)
+		return err
+	})
+}
+
+// extractBundleChart extracts the files of the chart at given path of the bundle into given directory, returning the
+// registry digest recorded alongside it, if any, or errNotMirrored if the bundle does not contain it.
+func extractBundleChart(bundle, chartPath, dir string) (string, error) {
+	file, err := os.Open(bundle)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return "", fmt.Errorf("reading bundle %s: %w", bundle, err)
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+
+	var (
+		found          bool
+		registryDigest string
+	)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return "", fmt.Errorf("reading bundle %s: %w", bundle, err)
+		}
+
+		if path.Clean(header.Name) == registryDigestPath(chartPath) {
+			data, err := io.ReadAll(tarReader)
+			if err != nil {
+				return "", fmt.Errorf("reading bundle %s: %w", bundle, err)
+			}
+			registryDigest = strings.TrimSpace(string(data))
+			continue
+		}
+
+		name, ok := strings.CutPrefix(path.Clean(header.Name), chartPath+"/")
+		if !ok {
+			continue
+		}
+		if !filepath.IsLocal(name) {
+			return "", fmt.Errorf("reading bundle %s: invalid file name: %s", bundle, header.Name)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return "", err
+			}
+		case tar.TypeReg:
+			if err := extractBundleFile(tarReader, target, header.FileInfo().Mode().Perm()); err != nil {
+				return "", err
+			}
+		default:
+			continue
+		}
+
+		found = true
+	}
+
+	if !found {
+		return "", errNotMirrored
+	}
+	return registryDigest, nil
+}
+
+func extractBundleFile(reader io.Reader, target string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		return err
+	}
+	return file.Close()
+}
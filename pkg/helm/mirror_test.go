@@ -0,0 +1,118 @@
+package helm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartMirror(t *testing.T) {
+	chart := Chart{RepoURL: "ghcr.io/acme/charts", Name: "app", Version: "1.0.0"}
+	registryDigest := "sha256:" + strings.Repeat("a", 64)
+
+	online := ChartCache{
+		Root: t.TempDir(),
+		Puller: &PullRendererMock{
+			PullFunc: func(ctx context.Context, opts PullOptions) (PullResult, error) {
+				dir := filepath.Join(opts.OutputDir, opts.Chart.Name)
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0o755))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: "+opts.Chart.Name), 0o644))
+				return PullResult{Digest: registryDigest}, os.WriteFile(filepath.Join(dir, "templates", "app.yaml"), []byte("kind: Deployment"), 0o644)
+			},
+		},
+	}
+
+	_, err := online.GetChartFS(context.Background(), chart)
+	require.NoError(t, err)
+
+	locked, err := online.LockChart(context.Background(), chart)
+	require.NoError(t, err)
+	digest := locked.ContentDigest
+
+	bundle := filepath.Join(t.TempDir(), "charts.tar.gz")
+	file, err := os.Create(bundle)
+	require.NoError(t, err)
+	require.NoError(t, online.WriteBundle(file, []Chart{chart}))
+	require.NoError(t, file.Close())
+
+	// Pulling offline would fail the test, as the puller mock has no PullFunc
+	newOfflineCache := func(mirror string) ChartCache {
+		return ChartCache{Root: t.TempDir(), Mirror: mirror, Offline: true, Puller: &PullRendererMock{}}
+	}
+
+	t.Run("not available offline", func(t *testing.T) {
+		_, err := newOfflineCache("").GetChartFS(context.Background(), chart)
+		require.ErrorIs(t, err, ErrChartUnavailable)
+		require.ErrorContains(t, err, "ghcr.io/acme/charts/app@1.0.0")
+	})
+
+	t.Run("mirror directory", func(t *testing.T) {
+		cache := newOfflineCache(online.Root)
+
+		locked := chart
+		locked.ContentDigest = digest
+
+		chartFS, err := cache.GetChartFS(context.Background(), locked)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(chartFS.DirName(), online.Root))
+
+		content, err := chartFS.ReadFile("templates/app.yaml")
+		require.NoError(t, err)
+		require.Equal(t, "kind: Deployment", string(content))
+
+		charts, err := cache.List()
+		require.NoError(t, err)
+		require.Empty(t, charts, "mirror directory is used in place")
+
+		locked.ContentDigest = "content-sha256:other"
+		_, err = cache.GetChartFS(context.Background(), locked)
+		require.ErrorIs(t, err, ErrContentDigestMismatch)
+
+		locked.ContentDigest = digest
+		locked.Digest = "sha256:other"
+		_, err = cache.GetChartFS(context.Background(), locked)
+		require.ErrorIs(t, err, ErrDigestMismatch)
+		locked.Digest = registryDigest
+
+		// Files modified in the mirror do not match the digest it recorded
+		locked.ContentDigest = digest
+		require.NoError(t, os.WriteFile(filepath.Join(chartFS.DirName(), "templates", "app.yaml"), []byte("kind: Job"), 0o644))
+		_, err = cache.GetChartFS(context.Background(), locked)
+		require.ErrorIs(t, err, ErrContentDigestMismatch)
+	})
+
+	t.Run("mirror bundle", func(t *testing.T) {
+		cache := newOfflineCache(bundle)
+
+		// The registry digest recorded when the chart was pulled is bundled along with it
+		pinned := chart
+		pinned.Digest = "sha256:other"
+		_, err := cache.GetChartFS(context.Background(), pinned)
+		require.ErrorIs(t, err, ErrDigestMismatch)
+
+		pinned.Digest = registryDigest
+		chartFS, err := cache.GetChartFS(context.Background(), pinned)
+		require.NoError(t, err)
+
+		content, err := chartFS.ReadFile("templates/app.yaml")
+		require.NoError(t, err)
+		require.Equal(t, "kind: Deployment", string(content))
+
+		charts, err := cache.List()
+		require.NoError(t, err)
+		require.Len(t, charts, 1, "bundled chart is extracted into the cache")
+
+		status, err := cache.Verify(charts[0])
+		require.NoError(t, err)
+		require.Equal(t, ChartValid, status)
+
+		other := chart
+		other.Version = "2.0.0"
+		_, err = cache.GetChartFS(context.Background(), other)
+		require.ErrorIs(t, err, ErrChartUnavailable)
+	})
+}
@@ -18,7 +18,7 @@ var _ PullRenderer = &PullRendererMock{}
 //
 //		// make and configure a mocked PullRenderer
 //		mockedPullRenderer := &PullRendererMock{
-//			PullFunc: func(contextMoqParam context.Context, pullOptions PullOptions) error {
+//			PullFunc: func(contextMoqParam context.Context, pullOptions PullOptions) (PullResult, error) {
 //				panic("mock out the Pull method")
 //			},
 //			RenderFunc: func(ctx context.Context, opts RenderOpts) (string, error) {
@@ -32,7 +32,7 @@ var _ PullRenderer = &PullRendererMock{}
 //	}
 type PullRendererMock struct {
 	// PullFunc mocks the Pull method.
-	PullFunc func(contextMoqParam context.Context, pullOptions PullOptions) error
+	PullFunc func(contextMoqParam context.Context, pullOptions PullOptions) (PullResult, error)
 
 	// RenderFunc mocks the Render method.
 	RenderFunc func(ctx context.Context, opts RenderOpts) (string, error)
@@ -59,7 +59,7 @@ type PullRendererMock struct {
 }
 
 // Pull calls PullFunc.
-func (mock *PullRendererMock) Pull(contextMoqParam context.Context, pullOptions PullOptions) error {
+func (mock *PullRendererMock) Pull(contextMoqParam context.Context, pullOptions PullOptions) (PullResult, error) {
 	callInfo := struct {
 		ContextMoqParam context.Context
 		PullOptions     PullOptions
@@ -72,9 +72,10 @@ func (mock *PullRendererMock) Pull(contextMoqParam context.Context, pullOptions
 	mock.lockPull.Unlock()
 	if mock.PullFunc == nil {
 		var (
-			errOut error
+			pullResultOut PullResult
+			errOut        error
 		)
-		return errOut
+		return pullResultOut, errOut
 	}
 	return mock.PullFunc(contextMoqParam, pullOptions)
 }
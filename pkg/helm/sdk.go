@@ -0,0 +1,138 @@
+//go:build helmsdk
+
+package helm
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/release"
+
+	"github.com/nestoca/joy/internal"
+	"github.com/nestoca/joy/internal/observability"
+)
+
+// SDK pulls charts and renders them in-process with the Helm Go SDK, as CLI does with helm pull and helm template,
+// without requiring helm on PATH nor spawning a process per render.
+type SDK struct {
+	internal.IO
+	Debug bool
+}
+
+func newSDK(io internal.IO, debug bool) (PullRenderer, error) {
+	return SDK{IO: io, Debug: debug}, nil
+}
+
+func (sdk SDK) Pull(ctx context.Context, opts PullOptions) (PullResult, error) {
+	_, span := observability.StartTrace(ctx, "helm_pull")
+	defer span.End()
+
+	if opts.OutputDir == "" {
+		opts.OutputDir = "."
+	}
+
+	chartURL, err := opts.Chart.ToURL()
+	if err != nil {
+		return PullResult{}, fmt.Errorf("invalid chart url: %w", err)
+	}
+
+	settings := cli.New()
+
+	// Output is captured for the digest, which the registry client reports when pulling OCI charts
+	var output bytes.Buffer
+
+	registryClient, err := registry.NewClient(
+		registry.ClientOptDebug(sdk.Debug),
+		registry.ClientOptWriter(teeWriter(sdk.Err, &output)),
+		registry.ClientOptCredentialsFile(settings.RegistryConfig),
+	)
+	if err != nil {
+		return PullResult{}, fmt.Errorf("creating registry client: %w", err)
+	}
+
+	pull := action.NewPullWithOpts(action.WithConfig(&action.Configuration{RegistryClient: registryClient}))
+	pull.Settings = settings
+	pull.Untar = true
+	pull.UntarDir = opts.OutputDir
+	pull.DestDir = opts.OutputDir
+	pull.Version = opts.Chart.Version
+
+	ref := chartURL.String()
+
+	switch chartURL.Scheme {
+	case "http", "https":
+		repo, chart := path.Split(chartURL.Path)
+		chartURL.Path = repo
+		pull.RepoURL = chartURL.String()
+		ref = chart
+	}
+
+	if _, err := pull.Run(ref); err != nil {
+		return PullResult{}, fmt.Errorf("pulling %s: %w", ref, err)
+	}
+
+	var result PullResult
+	if match := pulledDigestRegex.FindStringSubmatch(output.String()); match != nil {
+		result.Digest = match[1]
+	}
+	return result, nil
+}
+
+func (sdk SDK) Render(ctx context.Context, opts RenderOpts) (result string, err error) {
+	ctx, span := observability.StartTrace(ctx, "helm_render")
+	defer span.End()
+
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+	}()
+
+	chart, err := loader.Load(strings.TrimPrefix(opts.ChartPath, "file://"))
+	if err != nil {
+		return "", fmt.Errorf("loading chart: %w", err)
+	}
+
+	// Same as helm template: a client-only dry-run install, whose templates are rendered by the helm engine
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...any) {}})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.ReleaseName = opts.ReleaseName
+	install.Namespace = cmp.Or(opts.Namespace, "default")
+
+	rel, err := install.RunWithContext(ctx, chart, opts.Values)
+	if err != nil {
+		return "", err
+	}
+
+	var manifests strings.Builder
+	fmt.Fprintln(&manifests, strings.TrimSpace(rel.Manifest))
+
+	for _, hook := range rel.Hooks {
+		if isTestHook(hook) {
+			continue
+		}
+		fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+
+	return manifests.String(), nil
+}
+
+// isTestHook reports whether given hook is a test, which are skipped as with helm template --skip-tests.
+func isTestHook(hook *release.Hook) bool {
+	for _, event := range hook.Events {
+		if event == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+//go:build !helmsdk
+
+package helm
+
+import (
+	"errors"
+
+	"github.com/nestoca/joy/internal"
+)
+
+func newSDK(internal.IO, bool) (PullRenderer, error) {
+	return nil, errors.New(`helm engine "sdk" is not available in this build of joy: rebuild it with "-tags helmsdk" or use the "cli" engine`)
+}
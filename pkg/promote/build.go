@@ -0,0 +1,102 @@
+package promote
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/build"
+	"github.com/nestoca/joy/internal/release/cross"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+type (
+	BuildResult    = build.Result
+	SkippedRelease = build.SkippedRelease
+)
+
+type BuildParams struct {
+	// Catalog contains the releases of the project.
+	Catalog *catalog.Catalog
+
+	// Environment is the name of the environment in which to promote the build.
+	Environment string
+
+	// Project is the name of the project whose releases to promote.
+	Project string
+
+	// Version is the version to promote releases to.
+	Version string
+
+	// ChartVersion is the optional chart version to promote releases to.
+	ChartVersion string
+
+	// ExcludeLabels are `key` or `key=value` selectors of releases to skip. Preview releases are always skipped.
+	ExcludeLabels []string
+
+	// Logger receives progress messages (discarded if nil).
+	Logger Logger
+}
+
+// PromoteBuild promotes all releases of a project in given environment to a new version, writing their files, as is
+// typically done at the end of a CI pipeline.
+func PromoteBuild(params BuildParams) (*BuildResult, error) {
+	if params.Catalog == nil {
+		return nil, errors.New("catalog is required")
+	}
+
+	cat, err := narrowToEnvironment(params.Catalog, params.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	out := newLineWriter(params.Logger)
+	defer out.Flush()
+
+	return build.Promote(build.Opts{
+		Catalog:       cat,
+		Writer:        yml.DiskWriter,
+		Environment:   params.Environment,
+		Project:       params.Project,
+		Version:       params.Version,
+		ChartVersion:  params.ChartVersion,
+		ExcludeLabels: params.ExcludeLabels,
+		Out:           out,
+	})
+}
+
+// narrowToEnvironment returns a copy of given catalog restricted to a single environment, as expected by build
+// promotion and previews, without altering the original catalog.
+func narrowToEnvironment(cat *catalog.Catalog, name string) (*catalog.Catalog, error) {
+	env, err := lookupEnvironment(cat, name)
+	if err != nil {
+		return nil, err
+	}
+
+	index := cat.Releases.GetEnvironmentIndexByName(env.Name)
+	if index == -1 {
+		return nil, fmt.Errorf("environment %s not found in releases", env.Name)
+	}
+
+	narrowed := &catalog.Catalog{
+		Dir:          cat.Dir,
+		Environments: []*v1alpha1.Environment{env},
+		Projects:     cat.Projects,
+		Files:        cat.Files,
+	}
+	narrowed.Releases = cross.MakeReleaseList(narrowed.Environments)
+
+	for _, item := range cat.Releases.Items {
+		release := item.Releases[index]
+		if release == nil {
+			continue
+		}
+		narrowed.Releases.Items = append(narrowed.Releases.Items, &cross.Release{
+			Name:     item.Name,
+			Releases: []*v1alpha1.Release{release},
+		})
+	}
+
+	return narrowed, nil
+}
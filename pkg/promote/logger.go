@@ -0,0 +1,80 @@
+package promote
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Logger receives the progress messages of promotions, one line at a time, such as which release files are written
+// and which pull requests are created.
+type Logger interface {
+	Log(message string)
+}
+
+// LoggerFunc adapts a function to the Logger interface.
+type LoggerFunc func(message string)
+
+func (fn LoggerFunc) Log(message string) { fn(message) }
+
+// NopLogger discards all messages.
+var NopLogger Logger = LoggerFunc(func(string) {})
+
+// WriterLogger returns a Logger writing each message as a line to given writer.
+func WriterLogger(writer io.Writer) Logger {
+	return LoggerFunc(func(message string) {
+		_, _ = fmt.Fprintln(writer, message)
+	})
+}
+
+// lineWriter adapts a Logger to the io.Writer used internally for progress messages, logging each complete line.
+type lineWriter struct {
+	logger Logger
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func newLineWriter(logger Logger) *lineWriter {
+	if logger == nil {
+		logger = NopLogger
+	}
+	return &lineWriter{logger: logger}
+}
+
+func (w *lineWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buffer.Write(data)
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err != nil {
+			// Keep incomplete line until the rest of it is written
+			w.buffer.Reset()
+			w.buffer.WriteString(line)
+			return len(data), nil
+		}
+		w.log(line)
+	}
+}
+
+// Flush logs any remaining incomplete line.
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buffer.Len() > 0 {
+		w.log(w.buffer.String())
+		w.buffer.Reset()
+	}
+}
+
+func (w *lineWriter) log(line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	w.logger.Log(line)
+}
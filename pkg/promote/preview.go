@@ -0,0 +1,93 @@
+package promote
+
+import (
+	"errors"
+
+	"github.com/nestoca/joy/internal/patch"
+	"github.com/nestoca/joy/internal/preview"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+type (
+	PreviewResult = preview.Result
+	PatchOp       = patch.Op
+	Replacement   = preview.Replacement
+)
+
+type PreviewParams struct {
+	// Catalog contains the source release.
+	Catalog *catalog.Catalog
+
+	// Environment is the name of the environment of the source release.
+	Environment string
+
+	// Release is the name of the source release.
+	Release string
+
+	// Suffix is appended to the name of the source release to form the name of the preview, including any leading
+	// dash (e.g. "-pr-1234").
+	Suffix string
+
+	// Version is the version of the preview release (only used when creating a preview).
+	Version string
+
+	// Patches are RFC 6902 operations applied to the preview release (only used when creating a preview).
+	Patches []PatchOp
+
+	// Replacements are text replacements applied to the preview release file (only used when creating a preview).
+	Replacements []Replacement
+
+	// Logger receives progress messages (discarded if nil).
+	Logger Logger
+}
+
+// CreatePreview writes a preview copy of a release, labelled so that build promotions exclude it.
+func CreatePreview(params PreviewParams) (*PreviewResult, error) {
+	if params.Catalog == nil {
+		return nil, errors.New("catalog is required")
+	}
+
+	cat, err := narrowToEnvironment(params.Catalog, params.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	out := newLineWriter(params.Logger)
+	defer out.Flush()
+
+	return preview.Create(preview.CreateParams{
+		Catalog:  cat,
+		Writer:   yml.DiskWriter,
+		Env:      params.Environment,
+		Release:  params.Release,
+		Suffix:   params.Suffix,
+		Version:  params.Version,
+		Patches:  params.Patches,
+		Replaces: params.Replacements,
+		Out:      out,
+	})
+}
+
+// DeletePreview deletes the preview copy of a release, if it exists, and reports whether it was deleted.
+func DeletePreview(params PreviewParams) (bool, error) {
+	if params.Catalog == nil {
+		return false, errors.New("catalog is required")
+	}
+
+	cat, err := narrowToEnvironment(params.Catalog, params.Environment)
+	if err != nil {
+		return false, err
+	}
+
+	out := newLineWriter(params.Logger)
+	defer out.Flush()
+
+	return preview.Delete(preview.DeleteParams{
+		Catalog: cat,
+		Env:     params.Environment,
+		Release: params.Release,
+		Suffix:  params.Suffix,
+		Out:     out,
+	})
+}
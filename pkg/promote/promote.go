@@ -0,0 +1,174 @@
+// Package promote exposes the operations that mutate the catalog, namely promoting releases across environments,
+// promoting a build to a new version and managing preview releases, for programs embedding joy.
+//
+// Operations never prompt and never write to stdout: they report their progress to a pluggable Logger and return
+// structured results.
+package promote
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/github"
+	"github.com/nestoca/joy/internal/info"
+	"github.com/nestoca/joy/internal/links"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+// Export internal promotion result types so that they can be worked with from code that use the public joy packages.
+type (
+	Result            = promote.Result
+	ReleaseResult     = promote.ReleaseResult
+	PullRequestResult = promote.PullRequestResult
+	Status            = promote.Status
+)
+
+const (
+	StatusPromoted         = promote.StatusPromoted
+	StatusNothingToPromote = promote.StatusNothingToPromote
+	StatusCanceled         = promote.StatusCanceled
+)
+
+type Params struct {
+	// Catalog contains the environments and releases to promote.
+	Catalog *catalog.Catalog
+
+	// Config provides the catalog directory in which to create the promotion branch, as well as the templates and
+	// GitHub organization used to render the pull request.
+	Config *config.Config
+
+	// Source and Target are the names of the environments to promote from and to.
+	Source string
+	Target string
+
+	// Path is the ordered list of names of environments to promote through, one hop at a time. When specified,
+	// Source and Target must be empty.
+	Path []string
+
+	// Stacked creates one pull request per hop of Path, each based on the previous one.
+	Stacked bool
+
+	// Releases are the names of releases to promote, unless All is set.
+	Releases []string
+	All      bool
+	Omit     []string
+
+	// IncludeDependencies also promotes the out of sync releases that selected releases depend on, which are otherwise
+	// only reported as warnings.
+	IncludeDependencies bool
+
+	// KeepPrerelease skips releases that are prereleases in target environment.
+	KeepPrerelease bool
+
+	AutoMerge bool
+	Draft     bool
+
+	// DryRun does not write any file nor create any branch or pull request.
+	DryRun bool
+
+	// LocalOnly writes the promoted release files without creating any branch or pull request.
+	LocalOnly bool
+
+	Reviewers         []string
+	TemplateVariables map[string]string
+
+	// OverrideGates are the names of promotion gates to override, recording the override in the pull request.
+	OverrideGates []string
+
+	// Logger receives progress messages (discarded if nil).
+	Logger Logger
+}
+
+// Promote promotes releases from source to target environment, or along given path of environments, and creates the
+// corresponding pull request(s).
+func Promote(params Params) (*Result, error) {
+	if params.Catalog == nil {
+		return nil, errors.New("catalog is required")
+	}
+	if params.Config == nil {
+		return nil, errors.New("config is required")
+	}
+	if len(params.Releases) == 0 && !params.All {
+		return nil, errors.New("one of releases or all is required")
+	}
+
+	opts := promote.Opts{
+		Catalog:             params.Catalog,
+		Releases:            params.Releases,
+		NoPrompt:            true,
+		AutoMerge:           params.AutoMerge,
+		All:                 params.All,
+		Omit:                params.Omit,
+		KeepPrerelease:      params.KeepPrerelease,
+		Draft:               params.Draft,
+		DryRun:              params.DryRun,
+		LocalOnly:           params.LocalOnly,
+		Reviewers:           params.Reviewers,
+		Stacked:             params.Stacked,
+		OverrideGates:       params.OverrideGates,
+		IncludeDependencies: params.IncludeDependencies,
+	}
+
+	if len(params.Path) > 0 {
+		if params.Source != "" || params.Target != "" {
+			return nil, errors.New("source and target cannot be combined with path")
+		}
+		for _, name := range params.Path {
+			env, err := lookupEnvironment(params.Catalog, name)
+			if err != nil {
+				return nil, err
+			}
+			opts.Path = append(opts.Path, env)
+		}
+	} else {
+		if params.Stacked {
+			return nil, errors.New("stacked requires path")
+		}
+		if params.Source == "" || params.Target == "" {
+			return nil, errors.New("source and target are required")
+		}
+		var err error
+		if opts.SourceEnv, err = lookupEnvironment(params.Catalog, params.Source); err != nil {
+			return nil, err
+		}
+		if opts.TargetEnv, err = lookupEnvironment(params.Catalog, params.Target); err != nil {
+			return nil, err
+		}
+	}
+
+	out := newLineWriter(params.Logger)
+	defer out.Flush()
+
+	cfg := params.Config
+	infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
+
+	promotion := promote.Promotion{
+		PromptProvider:      promote.NewInteractivePromptProvider(out),
+		GitProvider:         promote.NewShellGitProvider(cfg.CatalogDir),
+		PullRequestProvider: github.NewPullRequestProvider(cfg.CatalogDir),
+		YamlWriter:          yml.DiskWriter,
+		CommitTemplate:      cfg.Templates.Release.Promote.Commit,
+		PullRequestTemplate: cfg.Templates.Release.Promote.PullRequest,
+		TemplateVariables:   params.TemplateVariables,
+		InfoProvider:        infoProvider,
+		LinksProvider:       links.NewProvider(infoProvider, cfg.Templates),
+		Out:                 out,
+	}
+
+	return promotion.Promote(opts)
+}
+
+func lookupEnvironment(cat *catalog.Catalog, name string) (*v1alpha1.Environment, error) {
+	env, err := v1alpha1.GetEnvironmentByName(cat.Environments, name)
+	if err != nil {
+		return nil, err
+	}
+	if env == nil {
+		return nil, fmt.Errorf("environment name is required")
+	}
+	return env, nil
+}
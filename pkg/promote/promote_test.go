@@ -0,0 +1,272 @@
+package promote
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func writeCatalog(t *testing.T, extraFiles ...map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"projects/api.yaml": `apiVersion: joy.nesto.ca/v1alpha1
+kind: Project
+metadata:
+  name: api
+spec: {}
+`,
+		"environments/staging/env.yaml": `apiVersion: joy.nesto.ca/v1alpha1
+kind: Environment
+metadata:
+  name: staging
+spec:
+  order: 1
+`,
+		"environments/prod/env.yaml": `apiVersion: joy.nesto.ca/v1alpha1
+kind: Environment
+metadata:
+  name: prod
+spec:
+  order: 2
+  promotion:
+    fromEnvironments: [staging]
+`,
+		"environments/staging/releases/api.yaml": `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.2.0
+  chart:
+    repoUrl: acme.com/charts
+    name: generic
+    version: 1.0.0
+  values:
+    replicas: 1
+`,
+		"environments/prod/releases/api.yaml": `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: api
+spec:
+  project: api
+  version: 1.1.0
+  chart:
+    repoUrl: acme.com/charts
+    name: generic
+    version: 1.0.0
+  values:
+    replicas: !lock 3
+`,
+	}
+
+	for _, extra := range extraFiles {
+		maps.Copy(files, extra)
+	}
+
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	return dir
+}
+
+func loadCatalog(t *testing.T, dir string) *catalog.Catalog {
+	t.Helper()
+	cat, err := catalog.Load(context.Background(), dir, nil)
+	require.NoError(t, err)
+	return cat
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestPromoteLocalOnly(t *testing.T) {
+	dir := writeCatalog(t)
+	cat := loadCatalog(t, dir)
+
+	var messages []string
+	result, err := Promote(Params{
+		Catalog:   cat,
+		Config:    &config.Config{User: config.User{CatalogDir: dir}},
+		Source:    "staging",
+		Target:    "prod",
+		Releases:  []string{"api"},
+		LocalOnly: true,
+		Logger:    LoggerFunc(func(message string) { messages = append(messages, message) }),
+	})
+	require.NoError(t, err)
+
+	prodFile := filepath.Join(dir, "environments/prod/releases/api.yaml")
+	require.Equal(t, StatusPromoted, result.Status)
+	require.Equal(t, []string{prodFile}, result.Files)
+	require.Len(t, result.Releases, 1)
+	require.Equal(t, "1.1.0", result.Releases[0].FromVersion)
+	require.Equal(t, "1.2.0", result.Releases[0].ToVersion)
+	require.Empty(t, result.PullRequests)
+
+	require.Contains(t, readFile(t, prodFile), "version: 1.2.0")
+	require.Contains(t, readFile(t, prodFile), "replicas: !lock 3")
+	require.NotEmpty(t, messages)
+}
+
+func TestPromoteDependencies(t *testing.T) {
+	release := func(name, version string, dependsOn string) string {
+		return `apiVersion: joy.nesto.ca/v1alpha1
+kind: Release
+metadata:
+  name: ` + name + `
+spec:
+  project: api
+  version: ` + version + `
+  dependsOn: [` + dependsOn + `]
+  chart:
+    repoUrl: acme.com/charts
+    name: generic
+    version: 1.0.0
+`
+	}
+
+	dependencies := map[string]string{
+		"environments/staging/releases/api.yaml":   release("api", "1.2.0", "migrations"),
+		"environments/prod/releases/api.yaml":      release("api", "1.1.0", "migrations"),
+		"environments/staging/releases/db.yaml":    release("migrations", "1.2.0", ""),
+		"environments/prod/releases/db.yaml":       release("migrations", "1.1.0", ""),
+		"environments/staging/releases/zeta.yaml":  release("zeta", "1.2.0", ""),
+		"environments/prod/releases/zeta.yaml":     release("zeta", "1.1.0", ""),
+		"environments/staging/releases/alpha.yaml": release("alpha", "1.2.0", "api"),
+		"environments/prod/releases/alpha.yaml":    release("alpha", "1.1.0", "api"),
+	}
+
+	names := func(result *Result) []string {
+		var names []string
+		for _, release := range result.Releases {
+			names = append(names, release.Name)
+		}
+		return names
+	}
+
+	t.Run("warns about missing dependencies", func(t *testing.T) {
+		dir := writeCatalog(t, dependencies)
+
+		var messages []string
+		result, err := Promote(Params{
+			Catalog:   loadCatalog(t, dir),
+			Config:    &config.Config{User: config.User{CatalogDir: dir}},
+			Source:    "staging",
+			Target:    "prod",
+			Releases:  []string{"api"},
+			LocalOnly: true,
+			Logger:    LoggerFunc(func(message string) { messages = append(messages, message) }),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"api"}, names(result))
+		require.True(t, slices.ContainsFunc(messages, func(message string) bool {
+			return strings.Contains(message, "depends on") && strings.Contains(message, "migrations")
+		}))
+	})
+
+	t.Run("includes dependencies in dependency order", func(t *testing.T) {
+		dir := writeCatalog(t, dependencies)
+
+		result, err := Promote(Params{
+			Catalog:             loadCatalog(t, dir),
+			Config:              &config.Config{User: config.User{CatalogDir: dir}},
+			Source:              "staging",
+			Target:              "prod",
+			Releases:            []string{"alpha"},
+			IncludeDependencies: true,
+			LocalOnly:           true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"migrations", "api", "alpha"}, names(result))
+		require.Contains(t, readFile(t, filepath.Join(dir, "environments/prod/releases/db.yaml")), "version: 1.2.0")
+		require.Contains(t, readFile(t, filepath.Join(dir, "environments/prod/releases/zeta.yaml")), "version: 1.1.0")
+	})
+}
+
+func TestPromoteRequiresReleases(t *testing.T) {
+	dir := writeCatalog(t)
+
+	_, err := Promote(Params{
+		Catalog: loadCatalog(t, dir),
+		Config:  &config.Config{User: config.User{CatalogDir: dir}},
+		Source:  "staging",
+		Target:  "prod",
+	})
+	require.EqualError(t, err, "one of releases or all is required")
+}
+
+func TestPromoteBuild(t *testing.T) {
+	dir := writeCatalog(t)
+	cat := loadCatalog(t, dir)
+
+	result, err := PromoteBuild(BuildParams{
+		Catalog:     cat,
+		Environment: "prod",
+		Project:     "api",
+		Version:     "1.3.0",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"api"}, result.Promoted)
+
+	require.Contains(t, readFile(t, filepath.Join(dir, "environments/prod/releases/api.yaml")), "version: 1.3.0")
+	require.Contains(t, readFile(t, filepath.Join(dir, "environments/staging/releases/api.yaml")), "version: 1.2.0")
+
+	// The catalog passed in is left untouched
+	require.Len(t, cat.Environments, 2)
+	require.Len(t, cat.Releases.Items[0].Releases, 2)
+}
+
+func TestPreview(t *testing.T) {
+	dir := writeCatalog(t)
+	cat := loadCatalog(t, dir)
+
+	params := PreviewParams{
+		Catalog:     cat,
+		Environment: "staging",
+		Release:     "api",
+		Suffix:      "-pr-12",
+		Version:     "1.2.1-pr-12",
+	}
+
+	result, err := CreatePreview(params)
+	require.NoError(t, err)
+	require.Equal(t, "api-pr-12", result.Release)
+	require.Contains(t, readFile(t, result.File), "version: 1.2.1-pr-12")
+
+	deleted, err := DeletePreview(params)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	require.NoFileExists(t, result.File)
+}
+
+func TestLineWriter(t *testing.T) {
+	var messages []string
+	writer := newLineWriter(LoggerFunc(func(message string) { messages = append(messages, message) }))
+
+	_, _ = writer.Write([]byte("first line\nsecond "))
+	_, _ = writer.Write([]byte("line\n\nthird"))
+	require.Equal(t, []string{"first line", "second line"}, messages)
+
+	writer.Flush()
+	require.Equal(t, []string{"first line", "second line", "third"}, messages)
+}